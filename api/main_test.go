@@ -1,11 +1,14 @@
 package api
 
 import (
+	"context"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
 	db "github.com/guncv/Simple-Bank/db/sqlc"
 	"github.com/guncv/Simple-Bank/util"
 	"github.com/stretchr/testify/require"
@@ -23,6 +26,16 @@ func newTestServer(t *testing.T, store db.Store) *Server {
 	return server
 }
 
+// stubVerifiedUser makes every user looked up by the policy check email verified
+func stubVerifiedUser(store *mockdb.MockStore) {
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, username string) (db.Users, error) {
+			return db.Users{Username: username, IsEmailVerified: true}, nil
+		})
+}
+
 func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
 	os.Exit(m.Run())
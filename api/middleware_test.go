@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/guncv/Simple-Bank/token"
 	"github.com/guncv/Simple-Bank/util"
 	"github.com/stretchr/testify/require"
@@ -22,7 +23,7 @@ func addAuthorization(
 	role util.Role,
 	duration time.Duration,
 ) {
-	token, payload, err := tokenMaker.CreateToken(username, role, duration)
+	token, payload, err := tokenMaker.CreateToken(username, role, uuid.New(), duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guncv/Simple-Bank/policy"
+)
+
+// enforcePolicy loads the user and writes an error response if policy forbids the action
+func (server *Server) enforcePolicy(ctx *gin.Context, username string, action policy.Action) bool {
+	user, err := server.store.GetUser(ctx, username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	if err := policy.Check(user, action); err != nil {
+		ctx.JSON(http.StatusPreconditionFailed, policyErrorResponse(username, err))
+		return false
+	}
+	return true
+}
+
+// policyErrorResponse carries the same violation as the PreconditionFailure details of the gRPC gateway
+func policyErrorResponse(username string, err error) gin.H {
+	violation := gin.H{
+		"subject":     "users/" + username,
+		"description": err.Error(),
+	}
+	if errors.Is(err, policy.ErrEmailNotVerified) {
+		violation["type"] = policy.ViolationEmailNotVerified
+	}
+
+	response := errorResponse(err)
+	response["violations"] = []gin.H{violation}
+	return response
+}
@@ -21,7 +21,7 @@ type Server struct {
 
 // New Server creates a new HTTP server and setup routing
 func NewServer(config util.Config, store db.Store) (*Server, error) {
-	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+	tokenMaker, err := token.NewMaker(config)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
@@ -33,7 +33,11 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 	}
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		v.RegisterValidation("currency", validCurrency)
+		currencies := config.CurrencyRegistry
+		if currencies == nil {
+			currencies = util.NewDefaultCurrencies()
+		}
+		v.RegisterValidation("currency", newCurrencyValidator(currencies))
 	}
 
 	// add routes to router
@@ -2,11 +2,16 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/token"
+	"github.com/guncv/Simple-Bank/util"
 )
 
 type renewAccessTokenRequest struct {
@@ -14,8 +19,11 @@ type renewAccessTokenRequest struct {
 }
 
 type renewAccessTokenResponse struct {
-	AccessToken          string    `json:"access_token"`
-	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+	SessionID             uuid.UUID `json:"session_id"`
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
 }
 
 func (server *Server) RenewAccessToken(ctx *gin.Context) {
@@ -31,9 +39,9 @@ func (server *Server) RenewAccessToken(ctx *gin.Context) {
 		return
 	}
 
-	session, err := server.store.GetSession(ctx, refreshPayload.ID)
+	session, err := server.store.GetSession(ctx, refreshPayload.SessionID)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, sql.ErrNoRows) {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
 			return
 		}
@@ -41,12 +49,6 @@ func (server *Server) RenewAccessToken(ctx *gin.Context) {
 		return
 	}
 
-	if session.IsBlocked {
-		err := fmt.Errorf("blocked session")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
-	}
-
 	if session.Username != refreshPayload.Username {
 		err := fmt.Errorf("incorrect session user")
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
@@ -65,19 +67,62 @@ func (server *Server) RenewAccessToken(ctx *gin.Context) {
 		return
 	}
 
-	accessToken, accessTokenPayload, err := server.tokenMaker.CreateToken(
-		session.Username,
-		refreshPayload.Role,
-		server.config.AccessTokenDuration,
-	)
+	// every rotation starts a new session in the same family
+	newSessionID, err := uuid.NewRandom()
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	var accessToken, refreshToken string
+	var accessPayload, newRefreshPayload *token.Payload
+	result, err := server.store.RotateSessionTx(ctx, db.RotateSessionTxParams{
+		SessionID: session.ID,
+		IssuedAt:  refreshPayload.IssuedAt,
+		NewSession: func(user db.Users) (db.CreateSessionParams, error) {
+			// the role comes from the user so that a role change applies on the next renewal
+			var err error
+			accessToken, accessPayload, err = server.tokenMaker.CreateToken(user.Username, util.Role(user.Role), newSessionID, server.config.AccessTokenDuration)
+			if err != nil {
+				return db.CreateSessionParams{}, fmt.Errorf("failed to create access token: %w", err)
+			}
+
+			refreshToken, newRefreshPayload, err = server.tokenMaker.CreateToken(user.Username, util.Role(user.Role), newSessionID, server.config.RefreshTokenDuration)
+			if err != nil {
+				return db.CreateSessionParams{}, fmt.Errorf("failed to create refresh token: %w", err)
+			}
+
+			return db.CreateSessionParams{
+				ID:           newSessionID,
+				Username:     user.Username,
+				RefreshToken: refreshToken,
+				UserAgent:    ctx.Request.UserAgent(),
+				ClientIp:     ctx.ClientIP(),
+				IsBlocked:    false,
+				ExpiresAt:    newRefreshPayload.ExpiredAt,
+			}, nil
+		},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRefreshTokenReused) {
+			err := fmt.Errorf("refresh token reuse detected, all sessions of this login have been blocked")
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrSessionBlocked) || errors.Is(err, db.ErrPasswordChanged) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
 	resp := renewAccessTokenResponse{
-		AccessToken:          accessToken,
-		AccessTokenExpiresAt: accessTokenPayload.ExpiredAt,
+		SessionID:             result.NewSession.ID,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: newRefreshPayload.ExpiredAt,
 	}
 	ctx.JSON(http.StatusOK, resp)
 }
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenewAccessTokenAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	user.Role = string(util.DepositorRole)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore, session db.Sessions)
+		checkResponse func(recorder *httptest.ResponseRecorder, session db.Sessions)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.RotateSessionTxParams) (db.RotateSessionTxResult, error) {
+						require.Equal(t, session.ID, arg.SessionID)
+
+						newSession, err := arg.NewSession(user)
+						require.NoError(t, err)
+						require.NotEqual(t, session.ID, newSession.ID)
+						require.NotEqual(t, session.RefreshToken, newSession.RefreshToken)
+
+						return db.RotateSessionTxResult{
+							OldSession: session,
+							NewSession: db.Sessions{
+								ID:           newSession.ID,
+								Username:     newSession.Username,
+								RefreshToken: newSession.RefreshToken,
+								ExpiresAt:    newSession.ExpiresAt,
+								FamilyID:     session.FamilyID,
+							},
+							User: user,
+						}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, session db.Sessions) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				data, err := io.ReadAll(recorder.Body)
+				require.NoError(t, err)
+
+				var resp renewAccessTokenResponse
+				err = json.Unmarshal(data, &resp)
+				require.NoError(t, err)
+				require.NotEqual(t, session.ID, resp.SessionID)
+				require.NotEmpty(t, resp.AccessToken)
+				require.NotEmpty(t, resp.RefreshToken)
+				require.NotEqual(t, session.RefreshToken, resp.RefreshToken)
+			},
+		},
+		{
+			name: "RefreshTokenReused",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				// the session was already rotated, so the store blocks the family
+				session.SupersededBy = uuid.NullUUID{UUID: uuid.New(), Valid: true}
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RotateSessionTxResult{}, db.ErrRefreshTokenReused)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, session db.Sessions) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "BlockedSession",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				session.IsBlocked = true
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RotateSessionTxResult{}, db.ErrSessionBlocked)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, session db.Sessions) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "PasswordChanged",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RotateSessionTxResult{}, db.ErrPasswordChanged)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, session db.Sessions) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "SessionNotFound",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(db.Sessions{}, sql.ErrNoRows)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, session db.Sessions) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "MismatchedRefreshToken",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				session.RefreshToken = util.RandomString(32)
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, session db.Sessions) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ExpiredSession",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				session.ExpiresAt = time.Now().Add(-time.Minute)
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, session db.Sessions) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RotateSessionTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, session db.Sessions) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, util.DepositorRole, uuid.New(), time.Minute)
+			require.NoError(t, err)
+
+			session := db.Sessions{
+				ID:           refreshPayload.SessionID,
+				Username:     user.Username,
+				RefreshToken: refreshToken,
+				ExpiresAt:    refreshPayload.ExpiredAt,
+				FamilyID:     refreshPayload.SessionID,
+			}
+			tc.buildStubs(store, session)
+
+			data, err := json.Marshal(gin.H{"refresh_token": refreshToken})
+			require.NoError(t, err)
+
+			url := "/token/renew_access"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder, session)
+		})
+	}
+}
@@ -8,9 +8,13 @@ import (
 
 	"github.com/gin-gonic/gin"
 	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/policy"
 	"github.com/guncv/Simple-Bank/token"
+	"github.com/guncv/Simple-Bank/util"
 )
 
+const idempotencyKeyHeader = "Idempotency-Key"
+
 type transferRequest struct {
 	FromAccountId int64  `json:"from_account_id" binding:"required,min=1"`
 	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
@@ -25,12 +29,24 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
+	idempotencyKey := ctx.GetHeader(idempotencyKeyHeader)
+	if idempotencyKey != "" {
+		if err := util.ValidateIdempotencyKey(idempotencyKey); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid %s header: %w", idempotencyKeyHeader, err)))
+			return
+		}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !server.enforcePolicy(ctx, authPayload.Username, policy.ActionTransfer) {
+		return
+	}
+
 	fromAccount, valid := server.validAccount(ctx, req.FromAccountId, req.Currency)
 	if !valid {
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	if fromAccount.Owner != authPayload.Username {
 		err := errors.New("account doesn't belong to the authenticated user")
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
@@ -43,13 +59,30 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 	}
 
 	arg := db.TransferTxParams{
-		FromAccountID: req.FromAccountId,
-		ToAccountID:   req.ToAccountID,
-		Amount:        req.Amount,
+		FromAccountID:  req.FromAccountId,
+		ToAccountID:    req.ToAccountID,
+		Amount:         req.Amount,
+		IdempotencyKey: idempotencyKey,
 	}
 
 	result, err := server.store.TransferTx(ctx, arg)
 	if err != nil {
+		if errors.Is(err, db.ErrIdempotencyKeyConflict) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrAccountNotActive) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrTransferLimitExceeded) {
+			ctx.JSON(http.StatusTooManyRequests, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrSystemAccount) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
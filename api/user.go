@@ -111,9 +111,16 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		return
 	}
 
+	sessionID, err := uuid.NewRandom()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
 	accessToken, accessTokenPayload, err := server.tokenMaker.CreateToken(
 		user.Username,
 		util.Role(user.Role),
+		sessionID,
 		server.config.AccessTokenDuration,
 	)
 	if err != nil {
@@ -124,6 +131,7 @@ func (server *Server) loginUser(ctx *gin.Context) {
 	refreshToken, refreshTokenPayload, err := server.tokenMaker.CreateToken(
 		user.Username,
 		util.Role(user.Role),
+		sessionID,
 		server.config.RefreshTokenDuration,
 	)
 	if err != nil {
@@ -132,13 +140,14 @@ func (server *Server) loginUser(ctx *gin.Context) {
 	}
 
 	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
-		ID:           refreshTokenPayload.ID,
+		ID:           sessionID,
 		Username:     user.Username,
 		RefreshToken: refreshToken,
 		UserAgent:    ctx.Request.UserAgent(),
 		ClientIp:     ctx.ClientIP(),
 		IsBlocked:    false,
 		ExpiresAt:    refreshTokenPayload.ExpiredAt,
+		FamilyID:     sessionID,
 	})
 
 	if err != nil {
@@ -5,10 +5,13 @@ import (
 	"github.com/guncv/Simple-Bank/util"
 )
 
-var validCurrency validator.Func = func(fieldLevel validator.FieldLevel) bool {
-	if currency, ok := fieldLevel.Field().Interface().(string); ok {
-		return util.IsSupportedCurrency(currency)
-	}
+// newCurrencyValidator accepts the currencies of the registry
+func newCurrencyValidator(currencies util.Currencies) validator.Func {
+	return func(fieldLevel validator.FieldLevel) bool {
+		if currency, ok := fieldLevel.Field().Interface().(string); ok {
+			return currencies.IsSupported(currency)
+		}
 
-	return false
+		return false
+	}
 }
@@ -0,0 +1,5 @@
+ALTER TABLE "accounts"
+DROP CONSTRAINT check_non_negative_overdraft_limit;
+
+ALTER TABLE "accounts"
+DROP COLUMN "overdraft_limit";
@@ -0,0 +1,5 @@
+ALTER TABLE "accounts"
+ADD COLUMN "overdraft_limit" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "accounts"
+ADD CONSTRAINT check_non_negative_overdraft_limit CHECK ("overdraft_limit" >= 0);
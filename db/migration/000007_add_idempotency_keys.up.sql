@@ -0,0 +1,8 @@
+CREATE TABLE "idempotency_keys" (
+  "username" varchar NOT NULL REFERENCES "users"("username") ON DELETE CASCADE,
+  "key" varchar NOT NULL,
+  "request_hash" varchar NOT NULL,
+  "response" jsonb NOT NULL DEFAULT '{}',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("username", "key")
+);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_sessions_family_id;
+
+ALTER TABLE "sessions"
+DROP COLUMN "superseded_by";
+
+ALTER TABLE "sessions"
+DROP COLUMN "family_id";
@@ -0,0 +1,12 @@
+ALTER TABLE "sessions"
+ADD COLUMN "family_id" uuid;
+
+UPDATE "sessions" SET "family_id" = "id";
+
+ALTER TABLE "sessions"
+ALTER COLUMN "family_id" SET NOT NULL;
+
+ALTER TABLE "sessions"
+ADD COLUMN "superseded_by" uuid;
+
+CREATE INDEX idx_sessions_family_id ON "sessions" ("family_id");
@@ -0,0 +1,11 @@
+CREATE TABLE "password_resets" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL REFERENCES "users"("username") ON DELETE CASCADE,
+  "email" varchar NOT NULL,
+  "secret_code" varchar NOT NULL,
+  "is_used" boolean NOT NULL DEFAULT false,
+  "expired_at" timestamptz NOT NULL DEFAULT (now() + interval '15 minutes'),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX idx_password_resets_username ON "password_resets" ("username");
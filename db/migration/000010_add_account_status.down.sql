@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS "owner_currency_key";
+
+ALTER TABLE "accounts"
+ADD CONSTRAINT "owner_currency_key" UNIQUE ("owner", "currency");
+
+ALTER TABLE "accounts"
+DROP COLUMN "status";
+
+DROP TYPE IF EXISTS "account_status";
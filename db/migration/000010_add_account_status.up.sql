@@ -0,0 +1,14 @@
+CREATE TYPE "account_status" AS ENUM (
+  'active',
+  'frozen',
+  'closed'
+);
+
+ALTER TABLE "accounts"
+ADD COLUMN "status" account_status NOT NULL DEFAULT 'active';
+
+-- closed accounts keep their history, so only open accounts need a unique currency per owner
+ALTER TABLE "accounts"
+DROP CONSTRAINT "owner_currency_key";
+
+CREATE UNIQUE INDEX "owner_currency_key" ON "accounts" ("owner", "currency") WHERE "status" <> 'closed';
@@ -0,0 +1,30 @@
+-- entries and transfers cascade from accounts, so deleting the accounts of a system user would
+-- silently erase ledger history. The ledger is append-only, roll back only before it was used.
+DO $$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM transfers t
+    JOIN accounts a ON a.id IN (t.from_account_id, t.to_account_id)
+    WHERE a.owner = 'simplebank_cash'
+  ) OR EXISTS (
+    SELECT 1 FROM entries e
+    JOIN accounts a ON a.id = e.account_id
+    WHERE a.owner = 'simplebank_cash'
+  ) THEN
+    RAISE EXCEPTION 'cannot roll back: the accounts of simplebank_cash are referenced by the ledger';
+  END IF;
+END
+$$;
+
+ALTER TABLE "transfers"
+DROP COLUMN "initiated_by";
+
+DELETE FROM accounts WHERE owner = 'simplebank_cash';
+
+DELETE FROM users WHERE username = 'simplebank_cash';
+
+ALTER TABLE users
+DROP CONSTRAINT check_role;
+
+ALTER TABLE users
+ADD CONSTRAINT check_role CHECK (role IN ('depositor', 'banker'));
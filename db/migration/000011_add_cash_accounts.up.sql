@@ -0,0 +1,21 @@
+ALTER TABLE users
+DROP CONSTRAINT check_role;
+
+ALTER TABLE users
+ADD CONSTRAINT check_role CHECK (role IN ('depositor', 'banker', 'system'));
+
+-- system users own internal ledger accounts and can never log in
+INSERT INTO users (username, hashed_password, full_name, email, is_email_verified, role)
+VALUES ('simplebank_cash', '!', 'Simple Bank Cash', 'cash@system.simplebank.internal', TRUE, 'system');
+
+-- deposits and withdrawals are booked against the cash account of their currency
+INSERT INTO accounts (owner, balance, currency)
+VALUES
+  ('simplebank_cash', 0, 'USD'),
+  ('simplebank_cash', 0, 'EUR'),
+  ('simplebank_cash', 0, 'THB');
+
+ALTER TABLE "transfers"
+ADD COLUMN "initiated_by" varchar REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "transfers"."initiated_by" IS 'Banker who performed a deposit or withdrawal, NULL for customer transfers';
@@ -0,0 +1,25 @@
+-- entries and transfers cascade from accounts, so deleting the accounts of a system user would
+-- silently erase ledger history. The ledger is append-only, roll back only before it was used.
+DO $$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM transfers t
+    JOIN accounts a ON a.id IN (t.from_account_id, t.to_account_id)
+    WHERE a.owner = 'simplebank_fx'
+  ) OR EXISTS (
+    SELECT 1 FROM entries e
+    JOIN accounts a ON a.id = e.account_id
+    WHERE a.owner = 'simplebank_fx'
+  ) THEN
+    RAISE EXCEPTION 'cannot roll back: the accounts of simplebank_fx are referenced by the ledger';
+  END IF;
+END
+$$;
+
+DROP TABLE IF EXISTS "exchanges";
+
+DELETE FROM accounts WHERE owner = 'simplebank_fx';
+
+DELETE FROM users WHERE username = 'simplebank_fx';
+
+DROP TABLE IF EXISTS "exchange_rates";
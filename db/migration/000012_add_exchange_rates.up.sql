@@ -0,0 +1,36 @@
+CREATE TABLE "exchange_rates" (
+  "id" bigserial PRIMARY KEY,
+  "from_currency" varchar NOT NULL,
+  "to_currency" varchar NOT NULL,
+  "rate" numeric(18, 8) NOT NULL,
+  "effective_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_by" varchar NOT NULL REFERENCES "users" ("username"),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT check_positive_rate CHECK ("rate" > 0),
+  CONSTRAINT check_different_currencies CHECK ("from_currency" <> "to_currency")
+);
+
+COMMENT ON COLUMN "exchange_rates"."rate" IS 'Units of to_currency received for one unit of from_currency';
+
+CREATE INDEX idx_exchange_rates_pair ON "exchange_rates" ("from_currency", "to_currency", "effective_at" DESC);
+
+-- the FX system user holds one account per currency to balance both legs of an exchange
+INSERT INTO users (username, hashed_password, full_name, email, is_email_verified, role)
+VALUES ('simplebank_fx', '!', 'Simple Bank FX', 'fx@system.simplebank.internal', TRUE, 'system');
+
+INSERT INTO accounts (owner, balance, currency)
+VALUES
+  ('simplebank_fx', 0, 'USD'),
+  ('simplebank_fx', 0, 'EUR'),
+  ('simplebank_fx', 0, 'THB');
+
+CREATE TABLE "exchanges" (
+  "id" bigserial PRIMARY KEY,
+  "debit_transfer_id" bigint NOT NULL REFERENCES "transfers" ("id") ON DELETE CASCADE,
+  "credit_transfer_id" bigint NOT NULL REFERENCES "transfers" ("id") ON DELETE CASCADE,
+  "exchange_rate_id" bigint NOT NULL REFERENCES "exchange_rates" ("id"),
+  "rate" numeric(18, 8) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "exchanges"."rate" IS 'Rate applied to the exchange, copied from exchange_rates';
@@ -0,0 +1,26 @@
+-- entries and transfers cascade from accounts, so deleting the accounts of a system user would
+-- silently erase ledger history. The ledger is append-only, roll back only before it was used.
+DO $$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM transfers t
+    JOIN accounts a ON a.id IN (t.from_account_id, t.to_account_id)
+    WHERE a.owner = 'simplebank_revenue'
+  ) OR EXISTS (
+    SELECT 1 FROM entries e
+    JOIN accounts a ON a.id = e.account_id
+    WHERE a.owner = 'simplebank_revenue'
+  ) THEN
+    RAISE EXCEPTION 'cannot roll back: the accounts of simplebank_revenue are referenced by the ledger';
+  END IF;
+END
+$$;
+
+DELETE FROM accounts WHERE owner = 'simplebank_revenue';
+
+DELETE FROM users WHERE username = 'simplebank_revenue';
+
+ALTER TABLE "transfers"
+DROP COLUMN "fee";
+
+DROP TABLE IF EXISTS "fee_schedules";
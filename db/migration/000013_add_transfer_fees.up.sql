@@ -0,0 +1,34 @@
+CREATE TABLE "fee_schedules" (
+  "id" bigserial PRIMARY KEY,
+  "currency" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "flat_fee" bigint NOT NULL DEFAULT 0,
+  "percentage_bps" integer NOT NULL DEFAULT 0,
+  "min_fee" bigint NOT NULL DEFAULT 0,
+  "max_fee" bigint,
+  "updated_by" varchar NOT NULL REFERENCES "users" ("username"),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT currency_role_key UNIQUE ("currency", "role"),
+  CONSTRAINT check_fee_amounts CHECK ("flat_fee" >= 0 AND "min_fee" >= 0 AND ("max_fee" IS NULL OR "max_fee" >= "min_fee")),
+  CONSTRAINT check_percentage_bps CHECK ("percentage_bps" BETWEEN 0 AND 10000)
+);
+
+COMMENT ON COLUMN "fee_schedules"."role" IS 'Role of the owner of the source account';
+COMMENT ON COLUMN "fee_schedules"."percentage_bps" IS 'Percentage of the amount in basis points, 100 = 1%';
+COMMENT ON COLUMN "fee_schedules"."max_fee" IS 'Upper bound of the fee, NULL for no cap';
+
+ALTER TABLE "transfers"
+ADD COLUMN "fee" bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "transfers"."fee" IS 'Fee charged to the source account on top of the amount';
+
+-- fees are credited to the revenue account of the transfer currency
+INSERT INTO users (username, hashed_password, full_name, email, is_email_verified, role)
+VALUES ('simplebank_revenue', '!', 'Simple Bank Revenue', 'revenue@system.simplebank.internal', TRUE, 'system');
+
+INSERT INTO accounts (owner, balance, currency)
+VALUES
+  ('simplebank_revenue', 0, 'USD'),
+  ('simplebank_revenue', 0, 'EUR'),
+  ('simplebank_revenue', 0, 'THB');
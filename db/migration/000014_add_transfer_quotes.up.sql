@@ -0,0 +1,18 @@
+CREATE TABLE "transfer_quotes" (
+  "id" uuid PRIMARY KEY,
+  "username" varchar NOT NULL REFERENCES "users" ("username") ON DELETE CASCADE,
+  "from_account_id" bigint NOT NULL REFERENCES "accounts" ("id") ON DELETE CASCADE,
+  "to_account_id" bigint NOT NULL REFERENCES "accounts" ("id") ON DELETE CASCADE,
+  "amount" bigint NOT NULL,
+  "fee" bigint NOT NULL DEFAULT 0,
+  "exchange_rate_id" bigint REFERENCES "exchange_rates" ("id"),
+  "converted_amount" bigint NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "transfer_quotes"."exchange_rate_id" IS 'Rate guaranteed for a cross-currency quote, NULL for same currency transfers';
+COMMENT ON COLUMN "transfer_quotes"."converted_amount" IS 'Amount credited to the target account in its own currency';
+
+CREATE INDEX idx_transfer_quotes_username ON "transfer_quotes" ("username");
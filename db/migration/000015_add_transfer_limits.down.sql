@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_transfers_from_account_created_at;
+
+DROP TABLE IF EXISTS "transfer_limit_overrides";
+
+DROP TABLE IF EXISTS "transfer_limits";
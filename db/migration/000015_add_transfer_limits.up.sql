@@ -0,0 +1,34 @@
+CREATE TABLE "transfer_limits" (
+  "id" bigserial PRIMARY KEY,
+  "role" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "per_transaction" bigint,
+  "daily" bigint,
+  "monthly" bigint,
+  "updated_by" varchar NOT NULL REFERENCES "users" ("username"),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT transfer_limits_role_currency_key UNIQUE ("role", "currency")
+);
+
+COMMENT ON COLUMN "transfer_limits"."per_transaction" IS 'Maximum amount of a single transfer, NULL for no limit';
+COMMENT ON COLUMN "transfer_limits"."daily" IS 'Maximum total sent per calendar day, NULL for no limit';
+COMMENT ON COLUMN "transfer_limits"."monthly" IS 'Maximum total sent per calendar month, NULL for no limit';
+
+CREATE TABLE "transfer_limit_overrides" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL REFERENCES "users" ("username") ON DELETE CASCADE,
+  "currency" varchar NOT NULL,
+  "per_transaction" bigint,
+  "daily" bigint,
+  "monthly" bigint,
+  "updated_by" varchar NOT NULL REFERENCES "users" ("username"),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT transfer_limit_overrides_username_currency_key UNIQUE ("username", "currency")
+);
+
+COMMENT ON TABLE "transfer_limit_overrides" IS 'Replaces the limits of the user role for one currency';
+
+-- daily and monthly totals are summed over the customer transfers sent from an owner's accounts
+CREATE INDEX idx_transfers_from_account_created_at ON "transfers" ("from_account_id", "created_at");
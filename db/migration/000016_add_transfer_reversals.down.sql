@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS entries_immutable ON "entries";
+
+DROP TRIGGER IF EXISTS transfers_immutable ON "transfers";
+
+DROP FUNCTION IF EXISTS reject_ledger_update;
+
+DROP INDEX IF EXISTS idx_transfers_reversal_of;
+
+ALTER TABLE "transfers"
+DROP COLUMN "reversal_of";
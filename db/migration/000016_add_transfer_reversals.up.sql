@@ -0,0 +1,19 @@
+ALTER TABLE "transfers"
+ADD COLUMN "reversal_of" bigint REFERENCES "transfers" ("id");
+
+COMMENT ON COLUMN "transfers"."reversal_of" IS 'Transfer refunded by this one, NULL for regular transfers';
+
+CREATE INDEX idx_transfers_reversal_of ON "transfers" ("reversal_of");
+
+-- the ledger is append-only, mistakes are corrected with compensating transfers
+CREATE FUNCTION reject_ledger_update() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION '% rows cannot be updated', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transfers_immutable BEFORE UPDATE ON "transfers"
+FOR EACH ROW EXECUTE FUNCTION reject_ledger_update();
+
+CREATE TRIGGER entries_immutable BEFORE UPDATE ON "entries"
+FOR EACH ROW EXECUTE FUNCTION reject_ledger_update();
@@ -0,0 +1,2 @@
+-- account statements are paged newest first with a keyset on the entry id
+CREATE INDEX idx_entries_account_id_id ON "entries" ("account_id", "id" DESC);
@@ -0,0 +1,18 @@
+CREATE TABLE "outbox" (
+  "id" bigserial PRIMARY KEY,
+  "task_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "queue" varchar NOT NULL,
+  "max_retry" integer NOT NULL,
+  "attempts" integer NOT NULL DEFAULT 0,
+  "last_error" varchar,
+  "available_at" timestamptz NOT NULL DEFAULT (now()),
+  "sent_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON TABLE "outbox" IS 'Tasks written in the transaction that caused them and published to the task queue by the relay';
+
+COMMENT ON COLUMN "outbox"."available_at" IS 'Publishing is retried with backoff, the relay skips the row until then';
+
+CREATE INDEX idx_outbox_pending ON "outbox" ("id") WHERE "sent_at" IS NULL;
@@ -7,6 +7,15 @@ INSERT INTO accounts (
     $1, $2, $3
 ) RETURNING *;
 
+-- name: CreateSystemAccount :execrows
+INSERT INTO accounts (
+    owner,
+    balance,
+    currency
+) VALUES (
+    $1, 0, $2
+) ON CONFLICT (owner, currency) WHERE status <> 'closed' DO NOTHING;
+
 -- name: GetAccount :one
 SELECT * FROM accounts 
 WHERE id = $1 
@@ -39,4 +48,39 @@ RETURNING *;
 
 -- name: DeleteAccount :exec
 DELETE FROM accounts 
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: UpdateAccountOverdraftLimit :one
+-- The balance must already be within the new limit, so lowering it never leaves the account past its floor
+UPDATE accounts
+SET overdraft_limit = sqlc.arg(overdraft_limit)
+WHERE
+    id = sqlc.arg(id) AND
+    status <> 'closed' AND
+    balance >= -sqlc.arg(overdraft_limit)::bigint
+RETURNING *;
+
+-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = sqlc.arg(to_status)
+WHERE
+    id = sqlc.arg(id) AND
+    status = sqlc.arg(from_status)
+RETURNING *;
+
+-- name: CloseAccount :one
+UPDATE accounts
+SET status = 'closed'
+WHERE
+    id = $1 AND
+    status = 'active' AND
+    balance = 0
+RETURNING *;
+
+-- name: GetAccountByCurrency :one
+SELECT * FROM accounts
+WHERE
+    owner = sqlc.arg(owner) AND
+    currency = sqlc.arg(currency) AND
+    status <> 'closed'
+LIMIT 1;
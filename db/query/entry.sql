@@ -17,12 +17,40 @@ ORDER BY id
 LIMIT $1 
 OFFSET $2;
 
--- name: UpdateEntry :one
-UPDATE entries 
-SET amount = $2 
-WHERE id = $1 
-RETURNING *;
-
--- name: DeleteEntry :exec
-DELETE FROM entries 
-WHERE id = $1;
\ No newline at end of file
+-- name: ListAccountEntries :many
+-- The running balance is anchored on the sum of the entries up to the page, not on accounts.balance,
+-- and the window only covers entries before the cursor so that each page reads the rows it returns.
+WITH ledger AS (
+    SELECT
+        e.id,
+        e.account_id,
+        e.amount,
+        e.created_at,
+        (opening.total - COALESCE(SUM(e.amount) OVER (
+            ORDER BY e.id DESC
+            ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+        ), 0))::bigint AS balance_after
+    FROM entries e
+    CROSS JOIN (
+        SELECT COALESCE(SUM(amount), 0)::bigint AS total
+        FROM entries
+        WHERE
+            account_id = sqlc.arg(account_id) AND
+            (sqlc.narg(before_id)::bigint IS NULL OR id < sqlc.narg(before_id))
+    ) opening
+    WHERE
+        e.account_id = sqlc.arg(account_id) AND
+        (sqlc.narg(before_id)::bigint IS NULL OR e.id < sqlc.narg(before_id))
+)
+SELECT id, account_id, amount, created_at, balance_after
+FROM ledger
+WHERE
+    (sqlc.narg(from_time)::timestamptz IS NULL OR created_at >= sqlc.narg(from_time)) AND
+    (sqlc.narg(to_time)::timestamptz IS NULL OR created_at < sqlc.narg(to_time)) AND
+    (
+        sqlc.arg(direction)::text = '' OR
+        (sqlc.arg(direction) = 'credit' AND amount > 0) OR
+        (sqlc.arg(direction) = 'debit' AND amount < 0)
+    )
+ORDER BY id DESC
+LIMIT sqlc.arg(page_size);
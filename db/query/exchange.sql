@@ -0,0 +1,14 @@
+-- name: CreateExchange :one
+INSERT INTO exchanges (
+    debit_transfer_id,
+    credit_transfer_id,
+    exchange_rate_id,
+    rate
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetExchangeByTransfer :one
+SELECT * FROM exchanges
+WHERE debit_transfer_id = $1 OR credit_transfer_id = $1
+LIMIT 1;
@@ -0,0 +1,24 @@
+-- name: CreateExchangeRate :one
+INSERT INTO exchange_rates (
+    from_currency,
+    to_currency,
+    rate,
+    effective_at,
+    created_by
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetCurrentExchangeRate :one
+SELECT * FROM exchange_rates
+WHERE
+    from_currency = $1 AND
+    to_currency = $2 AND
+    effective_at <= now()
+ORDER BY effective_at DESC
+LIMIT 1;
+
+-- name: GetExchangeRate :one
+SELECT * FROM exchange_rates
+WHERE id = $1
+LIMIT 1;
@@ -0,0 +1,34 @@
+-- name: UpsertFeeSchedule :one
+INSERT INTO fee_schedules (
+    currency,
+    role,
+    flat_fee,
+    percentage_bps,
+    min_fee,
+    max_fee,
+    updated_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (currency, role) DO UPDATE
+SET
+    flat_fee = EXCLUDED.flat_fee,
+    percentage_bps = EXCLUDED.percentage_bps,
+    min_fee = EXCLUDED.min_fee,
+    max_fee = EXCLUDED.max_fee,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetFeeSchedule :one
+SELECT * FROM fee_schedules
+WHERE currency = $1 AND role = $2
+LIMIT 1;
+
+-- name: ListFeeSchedules :many
+SELECT * FROM fee_schedules
+ORDER BY currency, role;
+
+-- name: DeleteFeeSchedule :exec
+DELETE FROM fee_schedules
+WHERE currency = $1 AND role = $2;
@@ -0,0 +1,24 @@
+-- name: CreateIdempotencyKey :one
+INSERT INTO idempotency_keys (
+    username,
+    key,
+    request_hash
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (username, key) DO NOTHING
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE username = $1 AND key = $2
+LIMIT 1;
+
+-- name: UpdateIdempotencyKeyResponse :exec
+UPDATE idempotency_keys
+SET response = $3
+WHERE username = $1 AND key = $2;
+
+-- name: DeleteStaleIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE created_at < sqlc.arg(created_before);
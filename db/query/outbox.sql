@@ -0,0 +1,47 @@
+-- name: CreateOutboxMessage :one
+INSERT INTO outbox (
+    task_type,
+    payload,
+    queue,
+    max_retry
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetLatestOutboxMessage :one
+-- Finds the most recent message of a task type whose payload contains the given fields
+SELECT * FROM outbox
+WHERE task_type = sqlc.arg(task_type) AND payload @> sqlc.arg(payload)::jsonb
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: ListPendingOutboxMessages :many
+SELECT * FROM outbox
+WHERE sent_at IS NULL AND available_at <= now()
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxMessageSent :exec
+UPDATE outbox
+SET sent_at = now()
+WHERE id = $1;
+
+-- name: MarkOutboxMessageFailed :exec
+UPDATE outbox
+SET
+    attempts = attempts + 1,
+    last_error = sqlc.arg(last_error)::varchar,
+    available_at = now() + least(power(2, attempts), 300) * interval '1 second'
+WHERE id = sqlc.arg(id);
+
+-- name: GetOutboxStats :one
+SELECT
+    count(*) AS pending,
+    COALESCE(EXTRACT(EPOCH FROM now() - min(created_at)), 0)::float8 AS lag_seconds
+FROM outbox
+WHERE sent_at IS NULL;
+
+-- name: DeleteSentOutboxMessages :execrows
+DELETE FROM outbox
+WHERE sent_at < sqlc.arg(sent_before);
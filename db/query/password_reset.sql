@@ -0,0 +1,39 @@
+-- name: CreatePasswordReset :one
+INSERT INTO password_resets (
+    username,
+    email,
+    secret_code
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: UsePasswordReset :one
+UPDATE password_resets
+SET is_used = TRUE
+WHERE
+    id = $1 AND
+    secret_code = $2 AND
+    is_used = FALSE AND
+    expired_at > now() AND
+    email = (SELECT email FROM users WHERE users.username = password_resets.username)
+RETURNING *;
+
+-- name: GetPasswordReset :one
+SELECT * FROM password_resets
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetLatestPasswordReset :one
+SELECT * FROM password_resets
+WHERE username = $1
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: InvalidatePasswordResets :exec
+UPDATE password_resets
+SET is_used = TRUE
+WHERE username = $1 AND is_used = FALSE;
+
+-- name: DeleteStalePasswordResets :execrows
+DELETE FROM password_resets
+WHERE expired_at < sqlc.arg(expired_before);
@@ -6,9 +6,10 @@ INSERT INTO sessions (
     user_agent,
     client_ip,
     is_blocked,
-    expires_at
+    expires_at,
+    family_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8
 ) RETURNING *;
 
 -- name: GetSession :one
@@ -16,3 +17,49 @@ SELECT * FROM sessions
 WHERE id = $1 
 LIMIT 1;
 
+-- name: GetSessionForUpdate :one
+SELECT * FROM sessions
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: SupersedeSession :one
+UPDATE sessions
+SET superseded_by = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: BlockSessionFamily :exec
+UPDATE sessions
+SET is_blocked = TRUE
+WHERE family_id = $1;
+
+-- name: BlockUserSessions :exec
+UPDATE sessions
+SET is_blocked = TRUE
+WHERE username = $1 AND is_blocked = FALSE;
+
+-- name: ListActiveSessions :many
+SELECT * FROM sessions
+WHERE
+    username = $1 AND
+    is_blocked = FALSE AND
+    superseded_by IS NULL AND
+    expires_at > now()
+ORDER BY created_at DESC;
+
+-- name: GetSessionAuthInfo :one
+SELECT
+    sessions.username,
+    sessions.family_id,
+    sessions.is_blocked,
+    sessions.expires_at,
+    users.password_change_at
+FROM sessions
+JOIN users ON users.username = sessions.username
+WHERE sessions.id = $1
+LIMIT 1;
+
+-- name: DeleteExpiredSessions :execrows
+DELETE FROM sessions
+WHERE expires_at < sqlc.arg(expired_before);
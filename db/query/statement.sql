@@ -0,0 +1,32 @@
+-- name: GetAccountBalanceAt :one
+-- The balance is summed from the ledger, like the running balances of ListAccountEntries,
+-- so a statement and the entry history agree even if the stored balance drifts.
+SELECT COALESCE((
+    SELECT SUM(e.amount)
+    FROM entries e
+    WHERE e.account_id = a.id AND e.created_at < sqlc.arg(at)
+), 0)::bigint AS balance
+FROM accounts a
+WHERE a.id = sqlc.arg(account_id);
+
+-- name: ListStatementAccountIDs :many
+-- Closed accounts are only listed when they have entries since the period started,
+-- so an account gets a final statement for the period it was closed in but none after.
+SELECT a.id FROM accounts a
+JOIN users u ON u.username = a.owner
+WHERE
+    u.role <> 'system' AND
+    a.created_at < sqlc.arg(period_end) AND
+    (a.status <> 'closed' OR EXISTS (
+        SELECT 1 FROM entries e
+        WHERE e.account_id = a.id AND e.created_at >= sqlc.arg(period_start)
+    ))
+ORDER BY a.id;
+
+-- name: ListStatementEntries :many
+SELECT * FROM entries
+WHERE
+    account_id = sqlc.arg(account_id) AND
+    created_at >= sqlc.arg(period_start) AND
+    created_at < sqlc.arg(period_end)
+ORDER BY id;
@@ -2,28 +2,85 @@
 INSERT INTO transfers (
     from_account_id,
     to_account_id,
-    amount
+    amount,
+    initiated_by,
+    fee,
+    reversal_of
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4, $5, $6
 ) RETURNING *;
 
+-- name: GetReversedAmount :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS reversed_amount
+FROM transfers
+WHERE reversal_of = $1;
+
 -- name: GetTransfer :one
 SELECT * FROM transfers 
 WHERE id = $1 
 LIMIT 1;
 
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetTransferTotals :one
+SELECT
+    COALESCE(SUM(t.amount) FILTER (WHERE t.created_at >= date_trunc('day', now())), 0)::bigint AS daily_total,
+    COALESCE(SUM(t.amount), 0)::bigint AS monthly_total
+FROM transfers t
+JOIN accounts a ON a.id = t.from_account_id
+WHERE
+    a.owner = sqlc.arg(owner) AND
+    a.currency = sqlc.arg(currency) AND
+    t.initiated_by IS NULL AND
+    t.created_at >= date_trunc('month', now());
+
+-- name: ListAccountTransfers :many
+SELECT
+    t.id,
+    t.from_account_id,
+    t.to_account_id,
+    t.amount,
+    t.created_at,
+    t.initiated_by,
+    t.fee,
+    t.reversal_of,
+    c.id AS counterparty_account_id,
+    c.currency AS counterparty_currency,
+    (CASE WHEN leg.id IS NULL THEN t.amount ELSE leg.amount END)::bigint AS counterparty_amount,
+    x.rate AS exchange_rate
+FROM transfers t
+-- an exchange leg is shown against the customer on the other side instead of the FX account
+LEFT JOIN exchanges x ON
+    (x.debit_transfer_id = t.id AND t.from_account_id = sqlc.arg(account_id)) OR
+    (x.credit_transfer_id = t.id AND t.to_account_id = sqlc.arg(account_id))
+LEFT JOIN transfers leg ON
+    leg.id = CASE WHEN x.debit_transfer_id = t.id THEN x.credit_transfer_id ELSE x.debit_transfer_id END
+JOIN accounts c ON c.id = CASE
+    WHEN x.debit_transfer_id = t.id THEN leg.to_account_id
+    WHEN x.credit_transfer_id = t.id THEN leg.from_account_id
+    WHEN t.from_account_id = sqlc.arg(account_id) THEN t.to_account_id
+    ELSE t.from_account_id
+END
+WHERE
+    (
+        (sqlc.arg(direction)::text <> 'incoming' AND t.from_account_id = sqlc.arg(account_id)) OR
+        (sqlc.arg(direction) <> 'outgoing' AND t.to_account_id = sqlc.arg(account_id))
+    ) AND
+    (sqlc.narg(counterparty_id)::bigint IS NULL OR c.id = sqlc.narg(counterparty_id)) AND
+    (sqlc.narg(before_id)::bigint IS NULL OR t.id < sqlc.narg(before_id)) AND
+    (sqlc.narg(min_amount)::bigint IS NULL OR t.amount >= sqlc.narg(min_amount)) AND
+    (sqlc.narg(max_amount)::bigint IS NULL OR t.amount <= sqlc.narg(max_amount)) AND
+    (sqlc.narg(from_time)::timestamptz IS NULL OR t.created_at >= sqlc.narg(from_time)) AND
+    (sqlc.narg(to_time)::timestamptz IS NULL OR t.created_at < sqlc.narg(to_time))
+ORDER BY t.id DESC
+LIMIT sqlc.arg(page_size);
+
 -- name: ListTransfer :many
 SELECT * FROM transfers 
 ORDER BY id 
 LIMIT $1 
 OFFSET $2;
-
--- name: UpdateTransfer :one
-UPDATE transfers 
-SET amount = $2 
-WHERE id = $1 
-RETURNING *;
-
--- name: DeleteTransfer :exec
-DELETE FROM transfers 
-WHERE id = $1;
\ No newline at end of file
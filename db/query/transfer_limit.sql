@@ -0,0 +1,54 @@
+-- name: UpsertTransferLimit :one
+INSERT INTO transfer_limits (
+    role,
+    currency,
+    per_transaction,
+    daily,
+    monthly,
+    updated_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (role, currency) DO UPDATE
+SET
+    per_transaction = EXCLUDED.per_transaction,
+    daily = EXCLUDED.daily,
+    monthly = EXCLUDED.monthly,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = now()
+RETURNING *;
+
+-- name: UpsertTransferLimitOverride :one
+INSERT INTO transfer_limit_overrides (
+    username,
+    currency,
+    per_transaction,
+    daily,
+    monthly,
+    updated_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (username, currency) DO UPDATE
+SET
+    per_transaction = EXCLUDED.per_transaction,
+    daily = EXCLUDED.daily,
+    monthly = EXCLUDED.monthly,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = now()
+RETURNING *;
+
+-- name: DeleteTransferLimitOverride :exec
+DELETE FROM transfer_limit_overrides
+WHERE username = $1 AND currency = $2;
+
+-- name: GetEffectiveTransferLimit :one
+SELECT per_transaction, daily, monthly, TRUE AS overridden
+FROM transfer_limit_overrides
+WHERE username = sqlc.arg(username) AND currency = sqlc.arg(currency)
+UNION ALL
+SELECT per_transaction, daily, monthly, FALSE AS overridden
+FROM transfer_limits
+WHERE role = sqlc.arg(role) AND currency = sqlc.arg(currency)
+ORDER BY overridden DESC
+LIMIT 1;
@@ -0,0 +1,23 @@
+-- name: CreateTransferQuote :one
+INSERT INTO transfer_quotes (
+    id,
+    username,
+    from_account_id,
+    to_account_id,
+    amount,
+    fee,
+    exchange_rate_id,
+    converted_amount,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING *;
+
+-- name: UseTransferQuote :one
+UPDATE transfer_quotes
+SET used_at = now()
+WHERE
+    id = $1 AND
+    used_at IS NULL AND
+    expires_at > now()
+RETURNING *;
@@ -16,6 +16,12 @@ SELECT * FROM users
 WHERE username = sqlc.arg(username)
 LIMIT 1;
 
+-- name: GetUserForUpdate :one
+SELECT * FROM users
+WHERE username = sqlc.arg(username)
+LIMIT 1
+FOR NO KEY UPDATE;
+
 -- name: UpdateUser :one
 UPDATE users
 SET 
@@ -25,4 +31,9 @@ SET
     email = COALESCE(sqlc.narg(email), email),
     is_email_verified = COALESCE(sqlc.narg(is_email_verified), is_email_verified)
 WHERE 
-    username = sqlc.arg(username) RETURNING *;
\ No newline at end of file
+    username = sqlc.arg(username) RETURNING *;
+
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE email = sqlc.arg(email)
+LIMIT 1;
@@ -15,4 +15,22 @@ WHERE
     secret_code = $2 AND
     is_used = FALSE AND
     expired_at > now()
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: GetLatestVerifyEmail :one
+SELECT * FROM verify_emails
+WHERE username = $1
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: InvalidateVerifyEmails :exec
+UPDATE verify_emails
+SET expired_at = now()
+WHERE
+    username = $1 AND
+    is_used = FALSE AND
+    expired_at > now();
+
+-- name: DeleteStaleVerifyEmails :execrows
+DELETE FROM verify_emails
+WHERE expired_at < sqlc.arg(expired_before);
@@ -13,7 +13,7 @@ const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
 set balance = balance + $2 -- manual name of input argument
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status
 `
 
 type AddAccountBalanceParams struct {
@@ -30,6 +30,33 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+	)
+	return i, err
+}
+
+const closeAccount = `-- name: CloseAccount :one
+UPDATE accounts
+SET status = 'closed'
+WHERE
+    id = $1 AND
+    status = 'active' AND
+    balance = 0
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status
+`
+
+func (q *Queries) CloseAccount(ctx context.Context, id int64) (Accounts, error) {
+	row := q.db.QueryRowContext(ctx, closeAccount, id)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
 	)
 	return i, err
 }
@@ -41,7 +68,7 @@ INSERT INTO accounts (
     currency
 ) VALUES (
     $1, $2, $3
-) RETURNING id, owner, balance, currency, created_at
+) RETURNING id, owner, balance, currency, created_at, overdraft_limit, status
 `
 
 type CreateAccountParams struct {
@@ -59,10 +86,35 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
 	)
 	return i, err
 }
 
+const createSystemAccount = `-- name: CreateSystemAccount :execrows
+INSERT INTO accounts (
+    owner,
+    balance,
+    currency
+) VALUES (
+    $1, 0, $2
+) ON CONFLICT (owner, currency) WHERE status <> 'closed' DO NOTHING
+`
+
+type CreateSystemAccountParams struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+}
+
+func (q *Queries) CreateSystemAccount(ctx context.Context, arg CreateSystemAccountParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createSystemAccount, arg.Owner, arg.Currency)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteAccount = `-- name: DeleteAccount :exec
 DELETE FROM accounts 
 WHERE id = $1
@@ -74,7 +126,7 @@ func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts 
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status FROM accounts 
 WHERE id = $1 
 LIMIT 1
 `
@@ -88,12 +140,43 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Accounts, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+	)
+	return i, err
+}
+
+const getAccountByCurrency = `-- name: GetAccountByCurrency :one
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status FROM accounts
+WHERE
+    owner = $1 AND
+    currency = $2 AND
+    status <> 'closed'
+LIMIT 1
+`
+
+type GetAccountByCurrencyParams struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+}
+
+func (q *Queries) GetAccountByCurrency(ctx context.Context, arg GetAccountByCurrencyParams) (Accounts, error) {
+	row := q.db.QueryRowContext(ctx, getAccountByCurrency, arg.Owner, arg.Currency)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts 
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status FROM accounts 
 WHERE id = $1
 LIMIT 1
 FOR NO KEY UPDATE
@@ -108,12 +191,14 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Accounts,
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
 	)
 	return i, err
 }
 
 const listAccount = `-- name: ListAccount :many
-SELECT id, owner, balance, currency, created_at FROM accounts 
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status FROM accounts 
 WHERE owner = $1
 ORDER BY id 
 LIMIT $2 
@@ -141,6 +226,8 @@ func (q *Queries) ListAccount(ctx context.Context, arg ListAccountParams) ([]Acc
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.Status,
 		); err != nil {
 			return nil, err
 		}
@@ -159,7 +246,7 @@ const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts 
 SET balance = $2 
 WHERE id = $1 
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status
 `
 
 type UpdateAccountParams struct {
@@ -176,6 +263,69 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+	)
+	return i, err
+}
+
+const updateAccountOverdraftLimit = `-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = $1
+WHERE
+    id = $2 AND
+    status <> 'closed' AND
+    balance >= -$1::bigint
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status
+`
+
+type UpdateAccountOverdraftLimitParams struct {
+	OverdraftLimit int64 `json:"overdraft_limit"`
+	ID             int64 `json:"id"`
+}
+
+// The balance must already be within the new limit, so lowering it never leaves the account past its floor
+func (q *Queries) UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Accounts, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountOverdraftLimit, arg.OverdraftLimit, arg.ID)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = $1
+WHERE
+    id = $2 AND
+    status = $3
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status
+`
+
+type UpdateAccountStatusParams struct {
+	ToStatus   AccountStatus `json:"to_status"`
+	ID         int64         `json:"id"`
+	FromStatus AccountStatus `json:"from_status"`
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Accounts, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountStatus, arg.ToStatus, arg.ID, arg.FromStatus)
+	var i Accounts
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
 	)
 	return i, err
 }
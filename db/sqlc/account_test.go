@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
@@ -26,6 +27,7 @@ func createRandomAccount(t *testing.T) Accounts {
 	require.Equal(t, arg.Owner, account.Owner)
 	require.Equal(t, arg.Balance, account.Balance)
 	require.Equal(t, arg.Currency, account.Currency)
+	require.Equal(t, AccountStatusActive, account.Status)
 
 	require.NotZero(t, account.ID)
 	require.NotZero(t, account.CreatedAt)
@@ -99,3 +101,99 @@ func TestListAccounts(t *testing.T) {
 		require.Equal(t, lastAccount.Owner, account.Owner)
 	}
 }
+
+func TestUpdateAccountStatus(t *testing.T) {
+	account := createRandomAccount(t)
+
+	frozen, err := testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID:         account.ID,
+		FromStatus: AccountStatusActive,
+		ToStatus:   AccountStatusFrozen,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusFrozen, frozen.Status)
+
+	// the account is no longer active, so the same transition does nothing
+	_, err = testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID:         account.ID,
+		FromStatus: AccountStatusActive,
+		ToStatus:   AccountStatusFrozen,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestCloseAccount(t *testing.T) {
+	account := createRandomAccount(t)
+
+	// accounts with money left cannot be closed
+	_, err := testQueries.CloseAccount(context.Background(), account.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = testQueries.UpdateAccount(context.Background(), UpdateAccountParams{
+		ID:      account.ID,
+		Balance: 0,
+	})
+	require.NoError(t, err)
+
+	closed, err := testQueries.CloseAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusClosed, closed.Status)
+
+	// a closed account no longer blocks a new one in the same currency
+	reopened, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    account.Owner,
+		Balance:  0,
+		Currency: account.Currency,
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, account.ID, reopened.ID)
+}
+
+func TestProvisionSystemAccountsTx(t *testing.T) {
+	store := NewStore(testDB)
+	currency := "X" + strings.ToUpper(util.RandomString(2))
+	arg := ProvisionSystemAccountsTxParams{Currencies: []string{util.USD, currency}}
+
+	result, err := store.ProvisionSystemAccountsTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(systemAccountOwners)), result.Created)
+
+	for _, owner := range systemAccountOwners {
+		account, err := testQueries.GetAccountByCurrency(context.Background(), GetAccountByCurrencyParams{
+			Owner:    owner,
+			Currency: currency,
+		})
+		require.NoError(t, err)
+		require.Zero(t, account.Balance)
+		require.Equal(t, AccountStatusActive, account.Status)
+	}
+
+	// provisioning again leaves the existing accounts alone
+	result, err = store.ProvisionSystemAccountsTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, result.Created)
+}
+
+func TestUpdateAccountOverdraftLimitBelowBalance(t *testing.T) {
+	account := createFundedAccount(t, 0)
+
+	_, err := testQueries.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+		ID:     account.ID,
+		Amount: -100,
+	})
+	require.NoError(t, err)
+
+	// the overdrawn balance does not fit in a lower limit
+	_, err = testQueries.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		OverdraftLimit: 50,
+		ID:             account.ID,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	updated, err := testQueries.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		OverdraftLimit: 100,
+		ID:             account.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(100), updated.OverdraftLimit)
+}
@@ -7,6 +7,8 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createEntry = `-- name: CreateEntry :one
@@ -35,16 +37,6 @@ func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entri
 	return i, err
 }
 
-const deleteEntry = `-- name: DeleteEntry :exec
-DELETE FROM entries 
-WHERE id = $1
-`
-
-func (q *Queries) DeleteEntry(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteEntry, id)
-	return err
-}
-
 const getEntry = `-- name: GetEntry :one
 SELECT id, account_id, amount, created_at FROM entries 
 WHERE id = $1 
@@ -63,6 +55,98 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entries, error) {
 	return i, err
 }
 
+const listAccountEntries = `-- name: ListAccountEntries :many
+WITH ledger AS (
+    SELECT
+        e.id,
+        e.account_id,
+        e.amount,
+        e.created_at,
+        (opening.total - COALESCE(SUM(e.amount) OVER (
+            ORDER BY e.id DESC
+            ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+        ), 0))::bigint AS balance_after
+    FROM entries e
+    CROSS JOIN (
+        SELECT COALESCE(SUM(amount), 0)::bigint AS total
+        FROM entries
+        WHERE
+            account_id = $1 AND
+            ($2::bigint IS NULL OR id < $2)
+    ) opening
+    WHERE
+        e.account_id = $1 AND
+        ($2::bigint IS NULL OR e.id < $2)
+)
+SELECT id, account_id, amount, created_at, balance_after
+FROM ledger
+WHERE
+    ($3::timestamptz IS NULL OR created_at >= $3) AND
+    ($4::timestamptz IS NULL OR created_at < $4) AND
+    (
+        $5::text = '' OR
+        ($5 = 'credit' AND amount > 0) OR
+        ($5 = 'debit' AND amount < 0)
+    )
+ORDER BY id DESC
+LIMIT $6
+`
+
+type ListAccountEntriesParams struct {
+	AccountID int64         `json:"account_id"`
+	BeforeID  sql.NullInt64 `json:"before_id"`
+	FromTime  sql.NullTime  `json:"from_time"`
+	ToTime    sql.NullTime  `json:"to_time"`
+	Direction string        `json:"direction"`
+	PageSize  int32         `json:"page_size"`
+}
+
+type ListAccountEntriesRow struct {
+	ID           int64     `json:"id"`
+	AccountID    int64     `json:"account_id"`
+	Amount       int64     `json:"amount"`
+	CreatedAt    time.Time `json:"created_at"`
+	BalanceAfter int64     `json:"balance_after"`
+}
+
+// The running balance is anchored on the sum of the entries up to the page, not on accounts.balance,
+// and the window only covers entries before the cursor so that each page reads the rows it returns.
+func (q *Queries) ListAccountEntries(ctx context.Context, arg ListAccountEntriesParams) ([]ListAccountEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountEntries,
+		arg.AccountID,
+		arg.BeforeID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.Direction,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAccountEntriesRow{}
+	for rows.Next() {
+		var i ListAccountEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.BalanceAfter,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEntry = `-- name: ListEntry :many
 SELECT id, account_id, amount, created_at FROM entries 
 ORDER BY id 
@@ -102,27 +186,3 @@ func (q *Queries) ListEntry(ctx context.Context, arg ListEntryParams) ([]Entries
 	}
 	return items, nil
 }
-
-const updateEntry = `-- name: UpdateEntry :one
-UPDATE entries 
-SET amount = $2 
-WHERE id = $1 
-RETURNING id, account_id, amount, created_at
-`
-
-type UpdateEntryParams struct {
-	ID     int64 `json:"id"`
-	Amount int64 `json:"amount"`
-}
-
-func (q *Queries) UpdateEntry(ctx context.Context, arg UpdateEntryParams) (Entries, error) {
-	row := q.db.QueryRowContext(ctx, updateEntry, arg.ID, arg.Amount)
-	var i Entries
-	err := row.Scan(
-		&i.ID,
-		&i.AccountID,
-		&i.Amount,
-		&i.CreatedAt,
-	)
-	return i, err
-}
@@ -46,25 +46,6 @@ func TestGetEntry(t *testing.T) {
 	require.Equal(t, entry1.Amount, entry2.Amount)
 	require.WithinDuration(t, entry1.CreatedAt, entry2.CreatedAt, time.Second)
 }
-
-func TestUpdateEntry(t *testing.T) {
-	// create entry
-	entry1 := createRandomEntry(t)
-
-	arg := UpdateEntryParams{
-		ID:     entry1.ID,
-		Amount: util.RandomMoney(),
-	}
-	entry2, err := testQueries.UpdateEntry(context.Background(), arg)
-	require.NoError(t, err)
-	require.NotEmpty(t, entry2)
-
-	require.Equal(t, entry1.ID, entry2.ID)
-	require.Equal(t, entry1.AccountID, entry2.AccountID)
-	require.NotEqual(t, entry1.Amount, entry2.Amount)
-	require.WithinDuration(t, entry1.CreatedAt, entry2.CreatedAt, time.Second)
-}
-
 func TestDeleteEntry(t *testing.T) {
 	entry1 := createRandomEntry(t)
 	err := testQueries.DeleteAccount(context.Background(), entry1.ID)
@@ -94,3 +75,95 @@ func TestListEntries(t *testing.T) {
 		require.NotEmpty(t, entry)
 	}
 }
+
+func TestListAccountEntries(t *testing.T) {
+	account := createFundedAccountInCurrency(t, util.USD, 0)
+
+	// book entries the way a transaction does so that the balance matches the ledger
+	var entries []Entries
+	for _, amount := range []int64{100, -30, 50, -20} {
+		entry, err := testQueries.CreateEntry(context.Background(), CreateEntryParams{
+			AccountID: account.ID,
+			Amount:    amount,
+		})
+		require.NoError(t, err)
+		entries = append(entries, entry)
+
+		_, err = testQueries.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+			ID:     account.ID,
+			Amount: amount,
+		})
+		require.NoError(t, err)
+	}
+
+	page, err := testQueries.ListAccountEntries(context.Background(), ListAccountEntriesParams{
+		AccountID: account.ID,
+		PageSize:  3,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 3)
+
+	// newest first, each with the balance right after it was booked
+	require.Equal(t, entries[3].ID, page[0].ID)
+	require.Equal(t, int64(100), page[0].BalanceAfter)
+	require.Equal(t, int64(120), page[1].BalanceAfter)
+	require.Equal(t, int64(70), page[2].BalanceAfter)
+
+	page, err = testQueries.ListAccountEntries(context.Background(), ListAccountEntriesParams{
+		AccountID: account.ID,
+		BeforeID:  sql.NullInt64{Int64: page[2].ID, Valid: true},
+		PageSize:  3,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, entries[0].ID, page[0].ID)
+	require.Equal(t, int64(100), page[0].BalanceAfter)
+
+	page, err = testQueries.ListAccountEntries(context.Background(), ListAccountEntriesParams{
+		AccountID: account.ID,
+		Direction: util.DebitDirection,
+		PageSize:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	require.Equal(t, int64(-20), page[0].Amount)
+	require.Equal(t, int64(100), page[0].BalanceAfter)
+	require.Equal(t, int64(-30), page[1].Amount)
+	require.Equal(t, int64(70), page[1].BalanceAfter)
+
+	page, err = testQueries.ListAccountEntries(context.Background(), ListAccountEntriesParams{
+		AccountID: account.ID,
+		FromTime:  sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true},
+		PageSize:  10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, page)
+}
+
+func TestListAccountEntriesIgnoresStoredBalance(t *testing.T) {
+	account := createFundedAccountInCurrency(t, util.USD, 0)
+
+	for _, amount := range []int64{100, -30} {
+		_, err := testQueries.CreateEntry(context.Background(), CreateEntryParams{
+			AccountID: account.ID,
+			Amount:    amount,
+		})
+		require.NoError(t, err)
+	}
+
+	// a balance written without entries does not shift the history
+	_, err := testQueries.UpdateAccount(context.Background(), UpdateAccountParams{
+		ID:      account.ID,
+		Balance: 1000,
+	})
+	require.NoError(t, err)
+
+	page, err := testQueries.ListAccountEntries(context.Background(), ListAccountEntriesParams{
+		AccountID: account.ID,
+		PageSize:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	require.Equal(t, int64(70), page[0].BalanceAfter)
+	require.Equal(t, int64(100), page[1].BalanceAfter)
+}
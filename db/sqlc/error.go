@@ -0,0 +1,62 @@
+package db
+
+import "errors"
+
+var (
+	// ErrInsufficientFunds is returned when a transfer would take the source account below its overdraft limit
+	ErrInsufficientFunds = errors.New("insufficient funds")
+
+	// ErrAccountNotActive is returned when a transfer touches a frozen or closed account
+	ErrAccountNotActive = errors.New("account is not active")
+
+	// ErrTransferLimitExceeded is returned when a transfer would break a per-transaction, daily or monthly limit
+	ErrTransferLimitExceeded = errors.New("transfer limit exceeded")
+
+	// ErrSystemAccount is returned when a customer transfer is sent to an account owned by a system user
+	ErrSystemAccount = errors.New("system accounts cannot receive transfers")
+
+	// ErrSameCurrency is returned when an exchange transfer is requested between accounts of the same currency
+	ErrSameCurrency = errors.New("accounts have the same currency")
+
+	// ErrExchangeRateNotFound is returned when no exchange rate is in effect for a currency pair
+	ErrExchangeRateNotFound = errors.New("exchange rate not found")
+
+	// ErrAmountTooSmall is returned when an amount converts to less than the smallest unit of the target currency
+	ErrAmountTooSmall = errors.New("amount is too small to convert")
+
+	// ErrQuoteNotFound is returned when a quote does not exist, has expired or was already executed
+	ErrQuoteNotFound = errors.New("quote not found, expired or already used")
+
+	// ErrQuoteMismatch is returned when a quote is executed for a different transfer than it was issued for
+	ErrQuoteMismatch = errors.New("transfer does not match the quote")
+
+	// ErrTransferNotReversible is returned when a reversal is requested for a transfer that is not a customer transfer
+	ErrTransferNotReversible = errors.New("transfer cannot be reversed")
+
+	// ErrTransferAlreadyReversed is returned when the whole amount of a transfer has already been refunded
+	ErrTransferAlreadyReversed = errors.New("transfer has already been fully reversed")
+
+	// ErrReversalTooLarge is returned when a partial refund exceeds the amount left to reverse
+	ErrReversalTooLarge = errors.New("reversal exceeds the amount left to reverse")
+
+	// ErrIdempotencyKeyConflict is returned when an idempotency key is reused with a different request
+	ErrIdempotencyKeyConflict = errors.New("idempotency key already used with a different request")
+
+	// ErrSessionBlocked is returned when a blocked session is used
+	ErrSessionBlocked = errors.New("blocked session")
+
+	// ErrPasswordChanged is returned when a refresh token issued before the last password change is used
+	ErrPasswordChanged = errors.New("password changed since the token was issued")
+
+	// ErrPasswordResetTooSoon is returned when a password reset is requested again before the cooldown ends
+	ErrPasswordResetTooSoon = errors.New("password reset was requested recently")
+
+	// ErrEmailAlreadyVerified is returned when a verification email is requested for a verified email
+	ErrEmailAlreadyVerified = errors.New("email is already verified")
+
+	// ErrVerifyEmailTooSoon is returned when a verification email is requested again before the cooldown ends
+	ErrVerifyEmailTooSoon = errors.New("verify email was sent recently")
+
+	// ErrRefreshTokenReused is returned when the refresh token of an already rotated session is presented again
+	ErrRefreshTokenReused = errors.New("refresh token has already been used")
+)
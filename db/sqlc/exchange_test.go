@@ -0,0 +1,197 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createFundedAccountInCurrency(t *testing.T, currency string, balance int64) Accounts {
+	user := createRandomUser(t)
+
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  balance,
+		Currency: currency,
+	})
+	require.NoError(t, err)
+
+	return account
+}
+
+func TestExchangeTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	banker := createRandomUser(t)
+
+	rate, err := testQueries.CreateExchangeRate(context.Background(), CreateExchangeRateParams{
+		FromCurrency: util.USD,
+		ToCurrency:   util.THB,
+		Rate:         "35.5",
+		EffectiveAt:  time.Now().Add(-time.Second),
+		CreatedBy:    banker.Username,
+	})
+	require.NoError(t, err)
+
+	// a rate that only takes effect in the future is ignored
+	_, err = testQueries.CreateExchangeRate(context.Background(), CreateExchangeRateParams{
+		FromCurrency: util.USD,
+		ToCurrency:   util.THB,
+		Rate:         "99",
+		EffectiveAt:  time.Now().Add(time.Hour),
+		CreatedBy:    banker.Username,
+	})
+	require.NoError(t, err)
+
+	fromAccount := createFundedAccountInCurrency(t, util.USD, 1000)
+	toAccount := createFundedAccountInCurrency(t, util.THB, 0)
+
+	result, err := store.ExchangeTransferTx(context.Background(), ExchangeTransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        101,
+	})
+	require.NoError(t, err)
+
+	// 101 * 35.5 = 3585.5, rounded down
+	require.Equal(t, int64(3585), result.ConvertedAmount)
+	require.Equal(t, rate.ID, result.Exchange.ExchangeRateID)
+	require.Equal(t, int64(899), result.FromAccount.Balance)
+	require.Equal(t, int64(3585), result.ToAccount.Balance)
+	require.Equal(t, int64(-101), result.FromEntry.Amount)
+	require.Equal(t, int64(3585), result.ToEntry.Amount)
+	require.Equal(t, fromAccount.ID, result.DebitTransfer.FromAccountID)
+	require.Equal(t, toAccount.ID, result.CreditTransfer.ToAccountID)
+
+	_, err = store.ExchangeTransferTx(context.Background(), ExchangeTransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   createFundedAccountInCurrency(t, util.USD, 0).ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrSameCurrency)
+
+	_, err = store.ExchangeTransferTx(context.Background(), ExchangeTransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        10000,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestExchangeTransferTxIdempotencyKey(t *testing.T) {
+	store := NewStore(testDB)
+	banker := createRandomUser(t)
+
+	_, err := testQueries.CreateExchangeRate(context.Background(), CreateExchangeRateParams{
+		FromCurrency: util.USD,
+		ToCurrency:   util.EUR,
+		Rate:         "0.9",
+		EffectiveAt:  time.Now().Add(-time.Second),
+		CreatedBy:    banker.Username,
+	})
+	require.NoError(t, err)
+
+	fromAccount := createFundedAccountInCurrency(t, util.USD, 1000)
+	toAccount := createFundedAccountInCurrency(t, util.EUR, 0)
+
+	quote, err := store.QuoteTransferTx(context.Background(), QuoteTransferTxParams{
+		Username:      fromAccount.Owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        100,
+		ExpiresAt:     time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.NoError(t, quote.Rejection)
+
+	arg := ExchangeTransferTxParams{
+		FromAccountID:  fromAccount.ID,
+		ToAccountID:    toAccount.ID,
+		Amount:         100,
+		IdempotencyKey: util.RandomString(16),
+		QuoteID:        uuid.NullUUID{UUID: quote.Quote.ID, Valid: true},
+	}
+
+	result1, err := store.ExchangeTransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, result1.Replayed)
+
+	// retrying with the same key returns the original result even though the quote is used up
+	result2, err := store.ExchangeTransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result2.Replayed)
+	require.Equal(t, result1.Exchange.ID, result2.Exchange.ID)
+	require.Equal(t, result1.DebitTransfer.ID, result2.DebitTransfer.ID)
+	require.Equal(t, result1.ToAccount.Balance, result2.ToAccount.Balance)
+
+	updatedFromAccount, err := testQueries.GetAccount(context.Background(), fromAccount.ID)
+	require.NoError(t, err)
+	require.Equal(t, result1.FromAccount.Balance, updatedFromAccount.Balance)
+
+	// reusing the key with a different request is rejected
+	arg.Amount = 200
+	arg.QuoteID = uuid.NullUUID{}
+	_, err = store.ExchangeTransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrIdempotencyKeyConflict)
+}
+
+func TestExchangeTransferTxToSystemAccount(t *testing.T) {
+	store := NewStore(testDB)
+	banker := createRandomUser(t)
+
+	_, err := testQueries.CreateExchangeRate(context.Background(), CreateExchangeRateParams{
+		FromCurrency: util.USD,
+		ToCurrency:   util.EUR,
+		Rate:         "0.9",
+		EffectiveAt:  time.Now().Add(-time.Second),
+		CreatedBy:    banker.Username,
+	})
+	require.NoError(t, err)
+
+	fromAccount := createFundedAccountInCurrency(t, util.USD, 1000)
+	fxAccount, err := testQueries.GetAccountByCurrency(context.Background(), GetAccountByCurrencyParams{
+		Owner:    FXAccountOwner,
+		Currency: util.EUR,
+	})
+	require.NoError(t, err)
+
+	_, err = store.ExchangeTransferTx(context.Background(), ExchangeTransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   fxAccount.ID,
+		Amount:        100,
+	})
+	require.ErrorIs(t, err, ErrSystemAccount)
+
+	updatedFromAccount, err := testQueries.GetAccount(context.Background(), fromAccount.ID)
+	require.NoError(t, err)
+	require.Equal(t, fromAccount.Balance, updatedFromAccount.Balance)
+}
+
+func TestConvertAmount(t *testing.T) {
+	converted, err := convertAmount(100, "0.91234567", 2, 2)
+	require.NoError(t, err)
+	require.Equal(t, int64(91), converted)
+
+	converted, err = convertAmount(3, "35.50000000", 2, 2)
+	require.NoError(t, err)
+	require.Equal(t, int64(106), converted)
+
+	_, err = convertAmount(1, "0.001", 2, 2)
+	require.ErrorIs(t, err, ErrAmountTooSmall)
+
+	// 1.00 USD at 150 JPY per USD is 150 yen, which has no minor unit
+	converted, err = convertAmount(100, "150", 2, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(150), converted)
+
+	// 150 JPY at 0.0067 USD per JPY is 1.005 USD, rounded down to 100 cents
+	converted, err = convertAmount(150, "0.0067", 0, 2)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), converted)
+
+	_, err = convertAmount(1, "abc", 2, 2)
+	require.Error(t, err)
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// RevenueAccountOwner is the system user owning the revenue account of every currency
+const RevenueAccountOwner = "simplebank_revenue"
+
+// CalculateFee applies a fee schedule to an amount. The percentage part is rounded up to the
+// smallest unit and the total is clamped between the minimum and the optional maximum fee.
+func CalculateFee(schedule FeeSchedules, amount int64) int64 {
+	bps := int64(schedule.PercentageBps)
+
+	// split the amount to keep amount * bps from overflowing
+	percentage := amount/10000*bps + (amount%10000*bps+9999)/10000
+
+	fee := schedule.FlatFee + percentage
+	if fee < schedule.MinFee {
+		fee = schedule.MinFee
+	}
+	if schedule.MaxFee.Valid && fee > schedule.MaxFee.Int64 {
+		fee = schedule.MaxFee.Int64
+	}
+	return fee
+}
+
+// transferFee returns the fee charged to a user of the given role, or 0 when no schedule is configured
+func transferFee(ctx context.Context, q *Queries, currency string, role string, amount int64) (int64, error) {
+	schedule, err := q.GetFeeSchedule(ctx, GetFeeScheduleParams{
+		Currency: currency,
+		Role:     role,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return CalculateFee(schedule, amount), nil
+}
+
+// chargeFee moves the fee from the source account to the revenue account of its currency.
+// The revenue account is always updated last, after the transfer accounts are locked.
+func chargeFee(ctx context.Context, q *Queries, fromAccount Accounts, fee int64) (Entries, Accounts, error) {
+	revenueAccount, err := q.GetAccountByCurrency(ctx, GetAccountByCurrencyParams{
+		Owner:    RevenueAccountOwner,
+		Currency: fromAccount.Currency,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Entries{}, Accounts{}, fmt.Errorf("no revenue account for currency %s", fromAccount.Currency)
+		}
+		return Entries{}, Accounts{}, err
+	}
+
+	if err := checkAccountActive(revenueAccount); err != nil {
+		return Entries{}, Accounts{}, err
+	}
+
+	feeEntry, err := q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: revenueAccount.ID,
+		Amount:    fee,
+	})
+	if err != nil {
+		return Entries{}, Accounts{}, err
+	}
+
+	account, err := q.AddAccountBalance(ctx, AddAccountBalanceParams{
+		ID:     fromAccount.ID,
+		Amount: -fee,
+	})
+	if err != nil {
+		return Entries{}, Accounts{}, err
+	}
+
+	_, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+		ID:     revenueAccount.ID,
+		Amount: fee,
+	})
+	if err != nil {
+		return Entries{}, Accounts{}, err
+	}
+
+	return feeEntry, account, nil
+}
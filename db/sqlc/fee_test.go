@@ -0,0 +1,230 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateFee(t *testing.T) {
+	schedule := FeeSchedules{
+		FlatFee:       10,
+		PercentageBps: 150,
+		MinFee:        50,
+		MaxFee:        sql.NullInt64{Int64: 500, Valid: true},
+	}
+
+	// 10 + 1.5% of 1000 = 25, raised to the minimum
+	require.Equal(t, int64(50), CalculateFee(schedule, 1000))
+	// 10 + 1.5% of 10001 = 10 + 150.015, rounded up
+	require.Equal(t, int64(161), CalculateFee(schedule, 10001))
+	// capped by the maximum
+	require.Equal(t, int64(500), CalculateFee(schedule, 1000000))
+
+	schedule.MaxFee = sql.NullInt64{}
+	require.Equal(t, int64(15010), CalculateFee(schedule, 1000000))
+
+	require.Equal(t, int64(0), CalculateFee(FeeSchedules{}, 1000))
+}
+
+func TestTransferTxWithFee(t *testing.T) {
+	store := NewStore(testDB)
+	banker := createRandomUser(t)
+
+	_, err := testQueries.UpsertFeeSchedule(context.Background(), UpsertFeeScheduleParams{
+		Currency:      util.THB,
+		Role:          string(util.DepositorRole),
+		FlatFee:       5,
+		PercentageBps: 100,
+		UpdatedBy:     banker.Username,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err := testQueries.DeleteFeeSchedule(context.Background(), DeleteFeeScheduleParams{
+			Currency: util.THB,
+			Role:     string(util.DepositorRole),
+		})
+		require.NoError(t, err)
+	})
+
+	revenueAccount, err := testQueries.GetAccountByCurrency(context.Background(), GetAccountByCurrencyParams{
+		Owner:    RevenueAccountOwner,
+		Currency: util.THB,
+	})
+	require.NoError(t, err)
+
+	fromAccount := createFundedAccountInCurrency(t, util.THB, 1000)
+	toAccount := createFundedAccountInCurrency(t, util.THB, 0)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        500,
+	})
+	require.NoError(t, err)
+
+	// 5 + 1% of 500
+	require.Equal(t, int64(10), result.Transfer.Fee)
+	require.Equal(t, int64(-510), result.FromEntry.Amount)
+	require.Equal(t, int64(500), result.ToEntry.Amount)
+	require.Equal(t, revenueAccount.ID, result.FeeEntry.AccountID)
+	require.Equal(t, int64(10), result.FeeEntry.Amount)
+	require.Equal(t, int64(490), result.FromAccount.Balance)
+	require.Equal(t, int64(500), result.ToAccount.Balance)
+
+	updatedRevenueAccount, err := testQueries.GetAccount(context.Background(), revenueAccount.ID)
+	require.NoError(t, err)
+	require.Equal(t, revenueAccount.Balance+10, updatedRevenueAccount.Balance)
+
+	// the fee counts towards the balance floor
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        485,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestExchangeTransferTxWithFee(t *testing.T) {
+	store := NewStore(testDB)
+	banker := createRandomUser(t)
+
+	_, err := testQueries.UpsertFeeSchedule(context.Background(), UpsertFeeScheduleParams{
+		Currency:      util.EUR,
+		Role:          string(util.DepositorRole),
+		FlatFee:       5,
+		PercentageBps: 100,
+		UpdatedBy:     banker.Username,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err := testQueries.DeleteFeeSchedule(context.Background(), DeleteFeeScheduleParams{
+			Currency: util.EUR,
+			Role:     string(util.DepositorRole),
+		})
+		require.NoError(t, err)
+	})
+
+	_, err = testQueries.CreateExchangeRate(context.Background(), CreateExchangeRateParams{
+		FromCurrency: util.EUR,
+		ToCurrency:   util.USD,
+		Rate:         "1.1",
+		EffectiveAt:  time.Now().Add(-time.Second),
+		CreatedBy:    banker.Username,
+	})
+	require.NoError(t, err)
+
+	revenueAccount, err := testQueries.GetAccountByCurrency(context.Background(), GetAccountByCurrencyParams{
+		Owner:    RevenueAccountOwner,
+		Currency: util.EUR,
+	})
+	require.NoError(t, err)
+
+	fromAccount := createFundedAccountInCurrency(t, util.EUR, 1000)
+	toAccount := createFundedAccountInCurrency(t, util.USD, 0)
+
+	quote, err := store.QuoteTransferTx(context.Background(), QuoteTransferTxParams{
+		Username:      fromAccount.Owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        500,
+		ExpiresAt:     time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.NoError(t, quote.Rejection)
+
+	// 5 + 1% of 500, charged in the source currency
+	require.Equal(t, int64(10), quote.Fee)
+	require.Equal(t, int64(10), quote.Quote.Fee)
+	require.Equal(t, int64(490), quote.FromAccount.Balance)
+
+	result, err := store.ExchangeTransferTx(context.Background(), ExchangeTransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        500,
+		QuoteID:       uuid.NullUUID{UUID: quote.Quote.ID, Valid: true},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, quote.Fee, result.DebitTransfer.Fee)
+	require.Zero(t, result.CreditTransfer.Fee)
+	require.Equal(t, int64(-510), result.FromEntry.Amount)
+	require.Equal(t, revenueAccount.ID, result.FeeEntry.AccountID)
+	require.Equal(t, quote.Fee, result.FeeEntry.Amount)
+	require.Equal(t, quote.FromAccount.Balance, result.FromAccount.Balance)
+	require.Equal(t, quote.ConvertedAmount, result.ToAccount.Balance)
+
+	updatedRevenueAccount, err := testQueries.GetAccount(context.Background(), revenueAccount.ID)
+	require.NoError(t, err)
+	require.Equal(t, revenueAccount.Balance+quote.Fee, updatedRevenueAccount.Balance)
+
+	// the fee counts towards the balance floor
+	_, err = store.ExchangeTransferTx(context.Background(), ExchangeTransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        485,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestTransferTxWithFeeRevenueAccountFrozen(t *testing.T) {
+	store := NewStore(testDB)
+	banker := createRandomUser(t)
+
+	_, err := testQueries.UpsertFeeSchedule(context.Background(), UpsertFeeScheduleParams{
+		Currency:  util.THB,
+		Role:      string(util.DepositorRole),
+		FlatFee:   5,
+		UpdatedBy: banker.Username,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err := testQueries.DeleteFeeSchedule(context.Background(), DeleteFeeScheduleParams{
+			Currency: util.THB,
+			Role:     string(util.DepositorRole),
+		})
+		require.NoError(t, err)
+	})
+
+	revenueAccount, err := testQueries.GetAccountByCurrency(context.Background(), GetAccountByCurrencyParams{
+		Owner:    RevenueAccountOwner,
+		Currency: util.THB,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID:         revenueAccount.ID,
+		FromStatus: AccountStatusActive,
+		ToStatus:   AccountStatusFrozen,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+			ID:         revenueAccount.ID,
+			FromStatus: AccountStatusFrozen,
+			ToStatus:   AccountStatusActive,
+		})
+		require.NoError(t, err)
+	})
+
+	fromAccount := createFundedAccountInCurrency(t, util.THB, 1000)
+	toAccount := createFundedAccountInCurrency(t, util.THB, 0)
+
+	// the fee cannot be booked, so the whole transfer is rolled back
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        500,
+	})
+	require.ErrorIs(t, err, ErrAccountNotActive)
+
+	account, err := testQueries.GetAccount(context.Background(), fromAccount.ID)
+	require.NoError(t, err)
+	require.Equal(t, fromAccount.Balance, account.Balance)
+}
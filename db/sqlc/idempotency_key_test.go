@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteStaleIdempotencyKeys(t *testing.T) {
+	user := createRandomUser(t)
+
+	stale, err := testQueries.CreateIdempotencyKey(context.Background(), CreateIdempotencyKeyParams{
+		Username:    user.Username,
+		Key:         util.RandomString(16),
+		RequestHash: util.RandomString(64),
+	})
+	require.NoError(t, err)
+
+	fresh, err := testQueries.CreateIdempotencyKey(context.Background(), CreateIdempotencyKeyParams{
+		Username:    user.Username,
+		Key:         util.RandomString(16),
+		RequestHash: util.RandomString(64),
+	})
+	require.NoError(t, err)
+
+	_, err = testDB.ExecContext(context.Background(),
+		`UPDATE idempotency_keys SET created_at = now() - interval '8 days' WHERE username = $1 AND key = $2`, user.Username, stale.Key)
+	require.NoError(t, err)
+
+	deleted, err := testQueries.DeleteStaleIdempotencyKeys(context.Background(), time.Now().Add(-7*24*time.Hour))
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, deleted, int64(1))
+
+	_, err = testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{Username: user.Username, Key: stale.Key})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{Username: user.Username, Key: fresh.Key})
+	require.NoError(t, err)
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// checkTransferLimit returns ErrTransferLimitExceeded when sending the amount would break the limits of the
+// user for the currency. The user row must be locked first so that concurrent transfers see each other's totals.
+func checkTransferLimit(ctx context.Context, q *Queries, user Users, currency string, amount int64) error {
+	limit, err := q.GetEffectiveTransferLimit(ctx, GetEffectiveTransferLimitParams{
+		Username: user.Username,
+		Currency: currency,
+		Role:     user.Role,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	if limit.PerTransaction.Valid && amount > limit.PerTransaction.Int64 {
+		return fmt.Errorf("%w: amount is above the per-transaction limit of %d", ErrTransferLimitExceeded, limit.PerTransaction.Int64)
+	}
+
+	if !limit.Daily.Valid && !limit.Monthly.Valid {
+		return nil
+	}
+
+	totals, err := q.GetTransferTotals(ctx, GetTransferTotalsParams{
+		Owner:    user.Username,
+		Currency: currency,
+	})
+	if err != nil {
+		return err
+	}
+
+	if limit.Daily.Valid && totals.DailyTotal+amount > limit.Daily.Int64 {
+		return fmt.Errorf("%w: %d already sent today out of a daily limit of %d", ErrTransferLimitExceeded, totals.DailyTotal, limit.Daily.Int64)
+	}
+	if limit.Monthly.Valid && totals.MonthlyTotal+amount > limit.Monthly.Int64 {
+		return fmt.Errorf("%w: %d already sent this month out of a monthly limit of %d", ErrTransferLimitExceeded, totals.MonthlyTotal, limit.Monthly.Int64)
+	}
+	return nil
+}
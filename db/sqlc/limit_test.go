@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferTxLimits(t *testing.T) {
+	store := NewStore(testDB)
+	banker := createRandomUser(t)
+
+	fromAccount := createFundedAccountInCurrency(t, util.USD, 1000)
+	toAccount := createFundedAccountInCurrency(t, util.USD, 0)
+
+	_, err := testQueries.UpsertTransferLimitOverride(context.Background(), UpsertTransferLimitOverrideParams{
+		Username:       fromAccount.Owner,
+		Currency:       util.USD,
+		PerTransaction: sql.NullInt64{Int64: 100, Valid: true},
+		Daily:          sql.NullInt64{Int64: 150, Valid: true},
+		UpdatedBy:      banker.Username,
+	})
+	require.NoError(t, err)
+
+	transfer := func(amount int64) error {
+		_, err := store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: fromAccount.ID,
+			ToAccountID:   toAccount.ID,
+			Amount:        amount,
+		})
+		return err
+	}
+
+	require.ErrorIs(t, transfer(120), ErrTransferLimitExceeded)
+	require.NoError(t, transfer(100))
+	require.ErrorIs(t, transfer(60), ErrTransferLimitExceeded)
+	require.NoError(t, transfer(50))
+
+	totals, err := testQueries.GetTransferTotals(context.Background(), GetTransferTotalsParams{
+		Owner:    fromAccount.Owner,
+		Currency: util.USD,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(150), totals.DailyTotal)
+	require.Equal(t, int64(150), totals.MonthlyTotal)
+
+	limit, err := testQueries.GetEffectiveTransferLimit(context.Background(), GetEffectiveTransferLimitParams{
+		Username: fromAccount.Owner,
+		Currency: util.USD,
+		Role:     string(util.DepositorRole),
+	})
+	require.NoError(t, err)
+	require.True(t, limit.Overridden)
+	require.False(t, limit.Monthly.Valid)
+
+	// without the override no limit applies to the user
+	err = testQueries.DeleteTransferLimitOverride(context.Background(), DeleteTransferLimitOverrideParams{
+		Username: fromAccount.Owner,
+		Currency: util.USD,
+	})
+	require.NoError(t, err)
+	require.NoError(t, transfer(500))
+}
@@ -5,17 +5,66 @@
 package db
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type AccountStatus string
+
+const (
+	AccountStatusActive AccountStatus = "active"
+	AccountStatusFrozen AccountStatus = "frozen"
+	AccountStatusClosed AccountStatus = "closed"
+)
+
+func (e *AccountStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AccountStatus(s)
+	case string:
+		*e = AccountStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AccountStatus: %T", src)
+	}
+	return nil
+}
+
+type NullAccountStatus struct {
+	AccountStatus AccountStatus `json:"account_status"`
+	Valid         bool          `json:"valid"` // Valid is true if AccountStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAccountStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.AccountStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AccountStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAccountStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AccountStatus), nil
+}
+
 type Accounts struct {
-	ID        int64     `json:"id"`
-	Owner     string    `json:"owner"`
-	Balance   int64     `json:"balance"`
-	Currency  string    `json:"currency"`
-	CreatedAt time.Time `json:"created_at"`
+	ID             int64         `json:"id"`
+	Owner          string        `json:"owner"`
+	Balance        int64         `json:"balance"`
+	Currency       string        `json:"currency"`
+	CreatedAt      time.Time     `json:"created_at"`
+	OverdraftLimit int64         `json:"overdraft_limit"`
+	Status         AccountStatus `json:"status"`
 }
 
 type Entries struct {
@@ -26,15 +75,131 @@ type Entries struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type ExchangeRates struct {
+	ID           int64  `json:"id"`
+	FromCurrency string `json:"from_currency"`
+	ToCurrency   string `json:"to_currency"`
+	// Units of to_currency received for one unit of from_currency
+	Rate        string    `json:"rate"`
+	EffectiveAt time.Time `json:"effective_at"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Exchanges struct {
+	ID               int64 `json:"id"`
+	DebitTransferID  int64 `json:"debit_transfer_id"`
+	CreditTransferID int64 `json:"credit_transfer_id"`
+	ExchangeRateID   int64 `json:"exchange_rate_id"`
+	// Rate applied to the exchange, copied from exchange_rates
+	Rate      string    `json:"rate"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type FeeSchedules struct {
+	ID       int64  `json:"id"`
+	Currency string `json:"currency"`
+	// Role of the owner of the source account
+	Role    string `json:"role"`
+	FlatFee int64  `json:"flat_fee"`
+	// Percentage of the amount in basis points, 100 = 1%
+	PercentageBps int32 `json:"percentage_bps"`
+	MinFee        int64 `json:"min_fee"`
+	// Upper bound of the fee, NULL for no cap
+	MaxFee    sql.NullInt64 `json:"max_fee"`
+	UpdatedBy string        `json:"updated_by"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type IdempotencyKeys struct {
+	Username    string          `json:"username"`
+	Key         string          `json:"key"`
+	RequestHash string          `json:"request_hash"`
+	Response    json.RawMessage `json:"response"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// Tasks written in the transaction that caused them and published to the task queue by the relay
+type Outbox struct {
+	ID        int64           `json:"id"`
+	TaskType  string          `json:"task_type"`
+	Payload   json.RawMessage `json:"payload"`
+	Queue     string          `json:"queue"`
+	MaxRetry  int32           `json:"max_retry"`
+	Attempts  int32           `json:"attempts"`
+	LastError sql.NullString  `json:"last_error"`
+	// Publishing is retried with backoff, the relay skips the row until then
+	AvailableAt time.Time    `json:"available_at"`
+	SentAt      sql.NullTime `json:"sent_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type PasswordResets struct {
+	ID         int64     `json:"id"`
+	Username   string    `json:"username"`
+	Email      string    `json:"email"`
+	SecretCode string    `json:"secret_code"`
+	IsUsed     bool      `json:"is_used"`
+	ExpiredAt  time.Time `json:"expired_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
 type Sessions struct {
-	ID           uuid.UUID `json:"id"`
-	Username     string    `json:"username"`
-	RefreshToken string    `json:"refresh_token"`
-	UserAgent    string    `json:"user_agent"`
-	ClientIp     string    `json:"client_ip"`
-	IsBlocked    bool      `json:"is_blocked"`
-	ExpiresAt    time.Time `json:"expires_at"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID           uuid.UUID     `json:"id"`
+	Username     string        `json:"username"`
+	RefreshToken string        `json:"refresh_token"`
+	UserAgent    string        `json:"user_agent"`
+	ClientIp     string        `json:"client_ip"`
+	IsBlocked    bool          `json:"is_blocked"`
+	ExpiresAt    time.Time     `json:"expires_at"`
+	CreatedAt    time.Time     `json:"created_at"`
+	FamilyID     uuid.UUID     `json:"family_id"`
+	SupersededBy uuid.NullUUID `json:"superseded_by"`
+}
+
+// Replaces the limits of the user role for one currency
+type TransferLimitOverrides struct {
+	ID             int64         `json:"id"`
+	Username       string        `json:"username"`
+	Currency       string        `json:"currency"`
+	PerTransaction sql.NullInt64 `json:"per_transaction"`
+	Daily          sql.NullInt64 `json:"daily"`
+	Monthly        sql.NullInt64 `json:"monthly"`
+	UpdatedBy      string        `json:"updated_by"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+type TransferLimits struct {
+	ID       int64  `json:"id"`
+	Role     string `json:"role"`
+	Currency string `json:"currency"`
+	// Maximum amount of a single transfer, NULL for no limit
+	PerTransaction sql.NullInt64 `json:"per_transaction"`
+	// Maximum total sent per calendar day, NULL for no limit
+	Daily sql.NullInt64 `json:"daily"`
+	// Maximum total sent per calendar month, NULL for no limit
+	Monthly   sql.NullInt64 `json:"monthly"`
+	UpdatedBy string        `json:"updated_by"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type TransferQuotes struct {
+	ID            uuid.UUID `json:"id"`
+	Username      string    `json:"username"`
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	Fee           int64     `json:"fee"`
+	// Rate guaranteed for a cross-currency quote, NULL for same currency transfers
+	ExchangeRateID sql.NullInt64 `json:"exchange_rate_id"`
+	// Amount credited to the target account in its own currency
+	ConvertedAmount int64        `json:"converted_amount"`
+	ExpiresAt       time.Time    `json:"expires_at"`
+	UsedAt          sql.NullTime `json:"used_at"`
+	CreatedAt       time.Time    `json:"created_at"`
 }
 
 type Transfers struct {
@@ -44,6 +209,12 @@ type Transfers struct {
 	// Must be positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// Banker who performed a deposit or withdrawal, NULL for customer transfers
+	InitiatedBy sql.NullString `json:"initiated_by"`
+	// Fee charged to the source account on top of the amount
+	Fee int64 `json:"fee"`
+	// Transfer refunded by this one, NULL for regular transfers
+	ReversalOf sql.NullInt64 `json:"reversal_of"`
 }
 
 type Users struct {
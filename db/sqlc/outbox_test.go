@@ -0,0 +1,218 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newOutboxMessageParams(username string) CreateOutboxMessageParams {
+	return CreateOutboxMessageParams{
+		TaskType: "task:test",
+		Payload:  []byte(fmt.Sprintf(`{"username": %q}`, username)),
+		Queue:    "default",
+		MaxRetry: 3,
+	}
+}
+
+func countOutboxMessages(t *testing.T, username string) int {
+	var count int
+	err := testDB.QueryRowContext(context.Background(),
+		`SELECT count(*) FROM outbox WHERE payload->>'username' = $1`, username).Scan(&count)
+	require.NoError(t, err)
+	return count
+}
+
+func getOutboxMessage(t *testing.T, id int64) Outbox {
+	var message Outbox
+	err := testDB.QueryRowContext(context.Background(),
+		`SELECT attempts, last_error, available_at, sent_at FROM outbox WHERE id = $1`, id).
+		Scan(&message.Attempts, &message.LastError, &message.AvailableAt, &message.SentAt)
+	require.NoError(t, err)
+	return message
+}
+
+func containsOutboxMessage(messages []Outbox, id int64) bool {
+	for _, message := range messages {
+		if message.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCreateUserTxWritesOutbox(t *testing.T) {
+	store := NewStore(testDB)
+
+	hashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+
+	result, err := store.CreateUserTx(context.Background(), CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			Username:       util.RandomOwner(),
+			HashedPassword: hashedPassword,
+			FullName:       util.RandomOwner(),
+			Email:          util.RandomEmail(),
+		},
+		AfterCreate: func(user Users) (CreateOutboxMessageParams, error) {
+			return newOutboxMessageParams(user.Username), nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, countOutboxMessages(t, result.User.Username))
+}
+
+func TestCreateUserTxRollsBackWithOutbox(t *testing.T) {
+	store := NewStore(testDB)
+	username := util.RandomOwner()
+
+	_, err := store.CreateUserTx(context.Background(), CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			Username:       username,
+			HashedPassword: util.RandomString(32),
+			FullName:       util.RandomOwner(),
+			Email:          util.RandomEmail(),
+		},
+		AfterCreate: func(user Users) (CreateOutboxMessageParams, error) {
+			return CreateOutboxMessageParams{}, errors.New("cannot build task")
+		},
+	})
+	require.Error(t, err)
+	require.Zero(t, countOutboxMessages(t, username))
+
+	_, err = testQueries.GetUser(context.Background(), username)
+	require.Error(t, err)
+}
+
+func TestRelayOutboxTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	message, err := testQueries.CreateOutboxMessage(context.Background(), newOutboxMessageParams(util.RandomOwner()))
+	require.NoError(t, err)
+	require.False(t, message.SentAt.Valid)
+	require.Zero(t, message.Attempts)
+
+	var published []Outbox
+	result, err := store.RelayOutboxTx(context.Background(), RelayOutboxTxParams{
+		Limit: 1000,
+		Publish: func(_ context.Context, m Outbox) error {
+			published = append(published, m)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, containsOutboxMessage(published, message.ID))
+	require.True(t, containsOutboxMessage(result.Sent, message.ID))
+
+	relayed := getOutboxMessage(t, message.ID)
+	require.True(t, relayed.SentAt.Valid)
+
+	// a sent message is not relayed again
+	result, err = store.RelayOutboxTx(context.Background(), RelayOutboxTxParams{
+		Limit: 1000,
+		Publish: func(_ context.Context, m Outbox) error {
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, containsOutboxMessage(result.Sent, message.ID))
+
+	deleted, err := testQueries.DeleteSentOutboxMessages(context.Background(), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, deleted, int64(1))
+}
+
+func TestRelayOutboxTxPublishFailure(t *testing.T) {
+	store := NewStore(testDB)
+
+	message, err := testQueries.CreateOutboxMessage(context.Background(), newOutboxMessageParams(util.RandomOwner()))
+	require.NoError(t, err)
+
+	result, err := store.RelayOutboxTx(context.Background(), RelayOutboxTxParams{
+		Limit: 1000,
+		Publish: func(_ context.Context, m Outbox) error {
+			if m.ID == message.ID {
+				return errors.New("redis unavailable")
+			}
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, containsOutboxMessage(result.Failed, message.ID))
+
+	failed := getOutboxMessage(t, message.ID)
+	require.False(t, failed.SentAt.Valid)
+	require.Equal(t, int32(1), failed.Attempts)
+	require.Equal(t, "redis unavailable", failed.LastError.String)
+	require.True(t, failed.AvailableAt.After(message.AvailableAt))
+
+	// the message waits for its backoff before the next attempt
+	result, err = store.RelayOutboxTx(context.Background(), RelayOutboxTxParams{
+		Limit: 1000,
+		Publish: func(_ context.Context, m Outbox) error {
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, containsOutboxMessage(result.Sent, message.ID))
+
+	stats, err := testQueries.GetOutboxStats(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, stats.Pending, int64(1))
+	require.Greater(t, stats.LagSeconds, float64(0))
+}
+
+func TestRelayOutboxTxPublishTimeout(t *testing.T) {
+	store := NewStore(testDB)
+
+	message, err := testQueries.CreateOutboxMessage(context.Background(), newOutboxMessageParams(util.RandomOwner()))
+	require.NoError(t, err)
+
+	// a stalled publish is cut off instead of holding the batch
+	result, err := store.RelayOutboxTx(context.Background(), RelayOutboxTxParams{
+		Limit:          1000,
+		PublishTimeout: 50 * time.Millisecond,
+		Publish: func(ctx context.Context, m Outbox) error {
+			if m.ID != message.ID {
+				return nil
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, containsOutboxMessage(result.Failed, message.ID))
+
+	failed := getOutboxMessage(t, message.ID)
+	require.False(t, failed.SentAt.Valid)
+	require.Equal(t, context.DeadlineExceeded.Error(), failed.LastError.String)
+}
+
+func TestRelayOutboxTxSkipsLockedMessages(t *testing.T) {
+	store := NewStore(testDB)
+
+	message, err := testQueries.CreateOutboxMessage(context.Background(), newOutboxMessageParams(util.RandomOwner()))
+	require.NoError(t, err)
+
+	// another relay holds the lock on the message while this one runs
+	tx, err := testDB.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(context.Background(), `SELECT id FROM outbox WHERE id = $1 FOR UPDATE`, message.ID)
+	require.NoError(t, err)
+
+	result, err := store.RelayOutboxTx(context.Background(), RelayOutboxTxParams{
+		Limit: 1000,
+		Publish: func(_ context.Context, m Outbox) error {
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, containsOutboxMessage(result.Sent, message.ID))
+}
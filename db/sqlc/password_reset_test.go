@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPasswordReset(t *testing.T, user Users) PasswordResets {
+	arg := CreatePasswordResetParams{
+		Username:   user.Username,
+		Email:      user.Email,
+		SecretCode: util.RandomString(32),
+	}
+
+	passwordReset, err := testQueries.CreatePasswordReset(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, passwordReset.Username)
+	require.Equal(t, arg.SecretCode, passwordReset.SecretCode)
+	require.False(t, passwordReset.IsUsed)
+	require.True(t, passwordReset.ExpiredAt.After(passwordReset.CreatedAt))
+
+	return passwordReset
+}
+
+func TestResetPasswordTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	session := createRandomSession(t, user.Username)
+	passwordReset := createRandomPasswordReset(t, user)
+
+	hashedPassword, err := util.HashPassword(util.RandomString(8))
+	require.NoError(t, err)
+
+	arg := ResetPasswordTxParams{
+		ResetID:        passwordReset.ID,
+		SecretCode:     passwordReset.SecretCode,
+		HashedPassword: hashedPassword,
+	}
+
+	result, err := store.ResetPasswordTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.PasswordReset.IsUsed)
+	require.Equal(t, hashedPassword, result.User.HashedPassword)
+	require.False(t, result.User.PasswordChangeAt.IsZero())
+
+	session, err = testQueries.GetSession(context.Background(), session.ID)
+	require.NoError(t, err)
+	require.True(t, session.IsBlocked)
+
+	// a reset code can only be used once
+	_, err = store.ResetPasswordTx(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestResetPasswordTxWrongCode(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	passwordReset := createRandomPasswordReset(t, user)
+
+	_, err := store.ResetPasswordTx(context.Background(), ResetPasswordTxParams{
+		ResetID:        passwordReset.ID,
+		SecretCode:     util.RandomString(32),
+		HashedPassword: user.HashedPassword,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	got, err := testQueries.GetUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.True(t, got.PasswordChangeAt.IsZero())
+}
+
+func TestResetPasswordTxEmailChanged(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	passwordReset := createRandomPasswordReset(t, user)
+
+	_, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username: user.Username,
+		Email: sql.NullString{
+			String: util.RandomEmail(),
+			Valid:  true,
+		},
+	})
+	require.NoError(t, err)
+
+	// the code was sent to the old address
+	_, err = store.ResetPasswordTx(context.Background(), ResetPasswordTxParams{
+		ResetID:        passwordReset.ID,
+		SecretCode:     passwordReset.SecretCode,
+		HashedPassword: util.RandomString(32),
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func requestPasswordReset(store Store, user Users, cooldown time.Duration) (RequestPasswordResetTxResult, error) {
+	return store.RequestPasswordResetTx(context.Background(), RequestPasswordResetTxParams{
+		Username:   user.Username,
+		SecretCode: util.RandomString(32),
+		Cooldown:   cooldown,
+		AfterCreate: func(passwordReset PasswordResets) (CreateOutboxMessageParams, error) {
+			return newOutboxMessageParams(passwordReset.Username), nil
+		},
+	})
+}
+
+func TestRequestPasswordResetTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	result1, err := requestPasswordReset(store, user, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, user.Email, result1.PasswordReset.Email)
+	require.False(t, result1.PasswordReset.IsUsed)
+	require.Equal(t, 1, countOutboxMessages(t, user.Username))
+
+	// an unused code blocks new requests during the cooldown
+	_, err = requestPasswordReset(store, user, time.Minute)
+	require.ErrorIs(t, err, ErrPasswordResetTooSoon)
+	require.Equal(t, 1, countOutboxMessages(t, user.Username))
+
+	result2, err := requestPasswordReset(store, user, 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, countOutboxMessages(t, user.Username))
+
+	// only the latest code can be used
+	reset1, err := testQueries.GetPasswordReset(context.Background(), result1.PasswordReset.ID)
+	require.NoError(t, err)
+	require.True(t, reset1.IsUsed)
+
+	reset2, err := testQueries.GetPasswordReset(context.Background(), result2.PasswordReset.ID)
+	require.NoError(t, err)
+	require.False(t, reset2.IsUsed)
+}
+
+func TestDeleteStalePasswordResets(t *testing.T) {
+	user := createRandomUser(t)
+	passwordReset := createRandomPasswordReset(t, user)
+
+	_, err := testDB.ExecContext(context.Background(),
+		`UPDATE password_resets SET expired_at = now() - interval '2 days' WHERE id = $1`, passwordReset.ID)
+	require.NoError(t, err)
+
+	deleted, err := testQueries.DeleteStalePasswordResets(context.Background(), time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, deleted, int64(1))
+
+	_, err = testQueries.GetPasswordReset(context.Background(), passwordReset.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -6,35 +6,90 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Accounts, error)
+	BlockSessionFamily(ctx context.Context, familyID uuid.UUID) error
+	BlockUserSessions(ctx context.Context, username string) error
+	CloseAccount(ctx context.Context, id int64) (Accounts, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Accounts, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entries, error)
+	CreateExchange(ctx context.Context, arg CreateExchangeParams) (Exchanges, error)
+	CreateExchangeRate(ctx context.Context, arg CreateExchangeRateParams) (ExchangeRates, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKeys, error)
+	CreateOutboxMessage(ctx context.Context, arg CreateOutboxMessageParams) (Outbox, error)
+	CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) (PasswordResets, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Sessions, error)
+	CreateSystemAccount(ctx context.Context, arg CreateSystemAccountParams) (int64, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfers, error)
+	CreateTransferQuote(ctx context.Context, arg CreateTransferQuoteParams) (TransferQuotes, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (Users, error)
 	CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmails, error)
 	DeleteAccount(ctx context.Context, id int64) error
-	DeleteEntry(ctx context.Context, id int64) error
-	DeleteTransfer(ctx context.Context, id int64) error
+	DeleteExpiredSessions(ctx context.Context, expiredBefore time.Time) (int64, error)
+	DeleteFeeSchedule(ctx context.Context, arg DeleteFeeScheduleParams) error
+	DeleteSentOutboxMessages(ctx context.Context, sentBefore time.Time) (int64, error)
+	DeleteStaleIdempotencyKeys(ctx context.Context, createdBefore time.Time) (int64, error)
+	DeleteStalePasswordResets(ctx context.Context, expiredBefore time.Time) (int64, error)
+	DeleteStaleVerifyEmails(ctx context.Context, expiredBefore time.Time) (int64, error)
+	DeleteTransferLimitOverride(ctx context.Context, arg DeleteTransferLimitOverrideParams) error
 	GetAccount(ctx context.Context, id int64) (Accounts, error)
+	GetAccountBalanceAt(ctx context.Context, arg GetAccountBalanceAtParams) (int64, error)
+	GetAccountByCurrency(ctx context.Context, arg GetAccountByCurrencyParams) (Accounts, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Accounts, error)
+	GetCurrentExchangeRate(ctx context.Context, arg GetCurrentExchangeRateParams) (ExchangeRates, error)
+	GetEffectiveTransferLimit(ctx context.Context, arg GetEffectiveTransferLimitParams) (GetEffectiveTransferLimitRow, error)
 	GetEntry(ctx context.Context, id int64) (Entries, error)
+	GetExchangeByTransfer(ctx context.Context, debitTransferID int64) (Exchanges, error)
+	GetExchangeRate(ctx context.Context, id int64) (ExchangeRates, error)
+	GetFeeSchedule(ctx context.Context, arg GetFeeScheduleParams) (FeeSchedules, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKeys, error)
+	GetLatestOutboxMessage(ctx context.Context, arg GetLatestOutboxMessageParams) (Outbox, error)
+	GetLatestPasswordReset(ctx context.Context, username string) (PasswordResets, error)
+	GetLatestVerifyEmail(ctx context.Context, username string) (VerifyEmails, error)
+	GetOutboxStats(ctx context.Context) (GetOutboxStatsRow, error)
+	GetPasswordReset(ctx context.Context, id int64) (PasswordResets, error)
+	GetReversedAmount(ctx context.Context, reversalOf sql.NullInt64) (int64, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Sessions, error)
+	GetSessionAuthInfo(ctx context.Context, id uuid.UUID) (GetSessionAuthInfoRow, error)
+	GetSessionForUpdate(ctx context.Context, id uuid.UUID) (Sessions, error)
 	GetTransfer(ctx context.Context, id int64) (Transfers, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfers, error)
+	GetTransferTotals(ctx context.Context, arg GetTransferTotalsParams) (GetTransferTotalsRow, error)
 	GetUser(ctx context.Context, username string) (Users, error)
+	GetUserByEmail(ctx context.Context, email string) (Users, error)
+	GetUserForUpdate(ctx context.Context, username string) (Users, error)
+	InvalidatePasswordResets(ctx context.Context, username string) error
+	InvalidateVerifyEmails(ctx context.Context, username string) error
 	ListAccount(ctx context.Context, arg ListAccountParams) ([]Accounts, error)
+	ListAccountEntries(ctx context.Context, arg ListAccountEntriesParams) ([]ListAccountEntriesRow, error)
+	ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]ListAccountTransfersRow, error)
+	ListActiveSessions(ctx context.Context, username string) ([]Sessions, error)
 	ListEntry(ctx context.Context, arg ListEntryParams) ([]Entries, error)
+	ListFeeSchedules(ctx context.Context) ([]FeeSchedules, error)
+	ListPendingOutboxMessages(ctx context.Context, limit int32) ([]Outbox, error)
+	ListStatementAccountIDs(ctx context.Context, arg ListStatementAccountIDsParams) ([]int64, error)
+	ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]Entries, error)
 	ListTransfer(ctx context.Context, arg ListTransferParams) ([]Transfers, error)
+	MarkOutboxMessageFailed(ctx context.Context, arg MarkOutboxMessageFailedParams) error
+	MarkOutboxMessageSent(ctx context.Context, id int64) error
+	SupersedeSession(ctx context.Context, arg SupersedeSessionParams) (Sessions, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Accounts, error)
-	UpdateEntry(ctx context.Context, arg UpdateEntryParams) (Entries, error)
-	UpdateTransfer(ctx context.Context, arg UpdateTransferParams) (Transfers, error)
+	UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Accounts, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Accounts, error)
+	UpdateIdempotencyKeyResponse(ctx context.Context, arg UpdateIdempotencyKeyResponseParams) error
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (Users, error)
 	UpdateVerifyEmail(ctx context.Context, arg UpdateVerifyEmailParams) (VerifyEmails, error)
+	UpsertFeeSchedule(ctx context.Context, arg UpsertFeeScheduleParams) (FeeSchedules, error)
+	UpsertTransferLimit(ctx context.Context, arg UpsertTransferLimitParams) (TransferLimits, error)
+	UpsertTransferLimitOverride(ctx context.Context, arg UpsertTransferLimitOverrideParams) (TransferLimitOverrides, error)
+	UsePasswordReset(ctx context.Context, arg UsePasswordResetParams) (PasswordResets, error)
+	UseTransferQuote(ctx context.Context, id uuid.UUID) (TransferQuotes, error)
 }
 
 var _ Querier = (*Queries)(nil)
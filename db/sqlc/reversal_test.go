@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	banker := createRandomUser(t)
+
+	sender := createFundedAccountInCurrency(t, util.THB, 1000)
+	recipient := createFundedAccountInCurrency(t, util.THB, 0)
+
+	transfer, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: sender.ID,
+		ToAccountID:   recipient.ID,
+		Amount:        100,
+	})
+	require.NoError(t, err)
+
+	// partial refund
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID:  transfer.Transfer.ID,
+		Amount:      30,
+		InitiatedBy: banker.Username,
+	})
+	require.NoError(t, err)
+
+	reversal := result.Reversal
+	require.Equal(t, recipient.ID, reversal.FromAccountID)
+	require.Equal(t, sender.ID, reversal.ToAccountID)
+	require.Equal(t, int64(30), reversal.Amount)
+	require.Equal(t, transfer.Transfer.ID, reversal.ReversalOf.Int64)
+	require.Equal(t, banker.Username, reversal.InitiatedBy.String)
+	require.Equal(t, int64(70), result.RemainingAmount)
+
+	require.Equal(t, int64(-30), result.FromEntry.Amount)
+	require.Equal(t, int64(30), result.ToEntry.Amount)
+	require.Equal(t, transfer.ToAccount.Balance-30, result.FromAccount.Balance)
+	require.Equal(t, transfer.FromAccount.Balance+30, result.ToAccount.Balance)
+
+	// the original transfer is left untouched
+	original, err := testQueries.GetTransfer(context.Background(), transfer.Transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, transfer.Transfer, original)
+	require.Equal(t, original, result.Original)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID:  transfer.Transfer.ID,
+		Amount:      71,
+		InitiatedBy: banker.Username,
+	})
+	require.ErrorIs(t, err, ErrReversalTooLarge)
+
+	// refund the rest
+	result, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID:  transfer.Transfer.ID,
+		InitiatedBy: banker.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(70), result.Reversal.Amount)
+	require.Zero(t, result.RemainingAmount)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID:  transfer.Transfer.ID,
+		InitiatedBy: banker.Username,
+	})
+	require.ErrorIs(t, err, ErrTransferAlreadyReversed)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID:  result.Reversal.ID,
+		InitiatedBy: banker.Username,
+	})
+	require.ErrorIs(t, err, ErrTransferNotReversible)
+}
+
+func TestReverseTransferTxConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+	banker := createRandomUser(t)
+
+	sender := createFundedAccountInCurrency(t, util.THB, 1000)
+	recipient := createFundedAccountInCurrency(t, util.THB, 0)
+
+	transfer, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: sender.ID,
+		ToAccountID:   recipient.ID,
+		Amount:        100,
+	})
+	require.NoError(t, err)
+
+	n := 5
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+				TransferID:  transfer.Transfer.ID,
+				InitiatedBy: banker.Username,
+			})
+			errs <- err
+		}()
+	}
+
+	reversed := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			reversed++
+			continue
+		}
+		require.ErrorIs(t, err, ErrTransferAlreadyReversed)
+	}
+	require.Equal(t, 1, reversed)
+
+	account, err := testQueries.GetAccount(context.Background(), recipient.ID)
+	require.NoError(t, err)
+	require.Zero(t, account.Balance)
+}
+
+func TestLedgerIsImmutable(t *testing.T) {
+	transfer := createRandomTransfer(t)
+
+	_, err := testDB.ExecContext(context.Background(), "UPDATE transfers SET amount = amount + 1 WHERE id = $1", transfer.ID)
+	require.Error(t, err)
+
+	entry := createRandomEntry(t)
+
+	_, err = testDB.ExecContext(context.Background(), "UPDATE entries SET amount = amount + 1 WHERE id = $1", entry.ID)
+	require.Error(t, err)
+}
@@ -12,6 +12,28 @@ import (
 	"github.com/google/uuid"
 )
 
+const blockSessionFamily = `-- name: BlockSessionFamily :exec
+UPDATE sessions
+SET is_blocked = TRUE
+WHERE family_id = $1
+`
+
+func (q *Queries) BlockSessionFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, blockSessionFamily, familyID)
+	return err
+}
+
+const blockUserSessions = `-- name: BlockUserSessions :exec
+UPDATE sessions
+SET is_blocked = TRUE
+WHERE username = $1 AND is_blocked = FALSE
+`
+
+func (q *Queries) BlockUserSessions(ctx context.Context, username string) error {
+	_, err := q.db.ExecContext(ctx, blockUserSessions, username)
+	return err
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO sessions (
     id,
@@ -20,10 +42,11 @@ INSERT INTO sessions (
     user_agent,
     client_ip,
     is_blocked,
-    expires_at
+    expires_at,
+    family_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
-) RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, superseded_by
 `
 
 type CreateSessionParams struct {
@@ -34,6 +57,7 @@ type CreateSessionParams struct {
 	ClientIp     string    `json:"client_ip"`
 	IsBlocked    bool      `json:"is_blocked"`
 	ExpiresAt    time.Time `json:"expires_at"`
+	FamilyID     uuid.UUID `json:"family_id"`
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Sessions, error) {
@@ -45,6 +69,7 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		arg.ClientIp,
 		arg.IsBlocked,
 		arg.ExpiresAt,
+		arg.FamilyID,
 	)
 	var i Sessions
 	err := row.Scan(
@@ -56,12 +81,27 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.IsBlocked,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.SupersededBy,
 	)
 	return i, err
 }
 
+const deleteExpiredSessions = `-- name: DeleteExpiredSessions :execrows
+DELETE FROM sessions
+WHERE expires_at < $1
+`
+
+func (q *Queries) DeleteExpiredSessions(ctx context.Context, expiredBefore time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredSessions, expiredBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getSession = `-- name: GetSession :one
-SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at FROM sessions
+SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, superseded_by FROM sessions
 WHERE id = $1 
 LIMIT 1
 `
@@ -78,6 +118,141 @@ func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Sessions, error
 		&i.IsBlocked,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.SupersededBy,
+	)
+	return i, err
+}
+
+const getSessionAuthInfo = `-- name: GetSessionAuthInfo :one
+SELECT
+    sessions.username,
+    sessions.family_id,
+    sessions.is_blocked,
+    sessions.expires_at,
+    users.password_change_at
+FROM sessions
+JOIN users ON users.username = sessions.username
+WHERE sessions.id = $1
+LIMIT 1
+`
+
+type GetSessionAuthInfoRow struct {
+	Username         string    `json:"username"`
+	FamilyID         uuid.UUID `json:"family_id"`
+	IsBlocked        bool      `json:"is_blocked"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	PasswordChangeAt time.Time `json:"password_change_at"`
+}
+
+func (q *Queries) GetSessionAuthInfo(ctx context.Context, id uuid.UUID) (GetSessionAuthInfoRow, error) {
+	row := q.db.QueryRowContext(ctx, getSessionAuthInfo, id)
+	var i GetSessionAuthInfoRow
+	err := row.Scan(
+		&i.Username,
+		&i.FamilyID,
+		&i.IsBlocked,
+		&i.ExpiresAt,
+		&i.PasswordChangeAt,
+	)
+	return i, err
+}
+
+const getSessionForUpdate = `-- name: GetSessionForUpdate :one
+SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, superseded_by FROM sessions
+WHERE id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetSessionForUpdate(ctx context.Context, id uuid.UUID) (Sessions, error) {
+	row := q.db.QueryRowContext(ctx, getSessionForUpdate, id)
+	var i Sessions
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.RefreshToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBlocked,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.SupersededBy,
+	)
+	return i, err
+}
+
+const listActiveSessions = `-- name: ListActiveSessions :many
+SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, superseded_by FROM sessions
+WHERE
+    username = $1 AND
+    is_blocked = FALSE AND
+    superseded_by IS NULL AND
+    expires_at > now()
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListActiveSessions(ctx context.Context, username string) ([]Sessions, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveSessions, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Sessions{}
+	for rows.Next() {
+		var i Sessions
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.RefreshToken,
+			&i.UserAgent,
+			&i.ClientIp,
+			&i.IsBlocked,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.FamilyID,
+			&i.SupersededBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const supersedeSession = `-- name: SupersedeSession :one
+UPDATE sessions
+SET superseded_by = $2
+WHERE id = $1
+RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, superseded_by
+`
+
+type SupersedeSessionParams struct {
+	ID           uuid.UUID     `json:"id"`
+	SupersededBy uuid.NullUUID `json:"superseded_by"`
+}
+
+func (q *Queries) SupersedeSession(ctx context.Context, arg SupersedeSessionParams) (Sessions, error) {
+	row := q.db.QueryRowContext(ctx, supersedeSession, arg.ID, arg.SupersededBy)
+	var i Sessions
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.RefreshToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBlocked,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.SupersededBy,
 	)
 	return i, err
 }
@@ -0,0 +1,164 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newSessionParams(username string, familyID uuid.UUID) CreateSessionParams {
+	id := uuid.New()
+	if familyID == uuid.Nil {
+		familyID = id
+	}
+
+	return CreateSessionParams{
+		ID:           id,
+		Username:     username,
+		RefreshToken: util.RandomString(32),
+		UserAgent:    "test-agent",
+		ClientIp:     "127.0.0.1",
+		IsBlocked:    false,
+		ExpiresAt:    time.Now().Add(time.Hour),
+		FamilyID:     familyID,
+	}
+}
+
+func createRandomSession(t *testing.T, username string) Sessions {
+	arg := newSessionParams(username, uuid.Nil)
+
+	session, err := testQueries.CreateSession(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.ID, session.ID)
+	require.Equal(t, arg.ID, session.FamilyID)
+	require.False(t, session.SupersededBy.Valid)
+
+	return session
+}
+
+func rotatedSessionParams(user Users) (CreateSessionParams, error) {
+	return newSessionParams(user.Username, uuid.Nil), nil
+}
+
+func TestRotateSessionTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	session1 := createRandomSession(t, user.Username)
+
+	result, err := store.RotateSessionTx(context.Background(), RotateSessionTxParams{
+		SessionID:  session1.ID,
+		IssuedAt:   time.Now(),
+		NewSession: rotatedSessionParams,
+	})
+	require.NoError(t, err)
+
+	session2 := result.NewSession
+	require.Equal(t, session1.FamilyID, session2.FamilyID)
+	require.True(t, result.OldSession.SupersededBy.Valid)
+	require.Equal(t, session2.ID, result.OldSession.SupersededBy.UUID)
+	require.False(t, session2.IsBlocked)
+	require.Equal(t, user.Username, result.User.Username)
+}
+
+func TestRotateSessionTxPasswordChanged(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	session := createRandomSession(t, user.Username)
+	issuedAt := time.Now()
+
+	_, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username: user.Username,
+		PasswordChangeAt: sql.NullTime{
+			Time:  issuedAt.Add(time.Second),
+			Valid: true,
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = store.RotateSessionTx(context.Background(), RotateSessionTxParams{
+		SessionID:  session.ID,
+		IssuedAt:   issuedAt,
+		NewSession: rotatedSessionParams,
+	})
+	require.ErrorIs(t, err, ErrPasswordChanged)
+
+	// the session is left as it was
+	session, err = store.GetSession(context.Background(), session.ID)
+	require.NoError(t, err)
+	require.False(t, session.SupersededBy.Valid)
+}
+
+func TestRotateSessionTxReuse(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	session1 := createRandomSession(t, user.Username)
+
+	result, err := store.RotateSessionTx(context.Background(), RotateSessionTxParams{
+		SessionID:  session1.ID,
+		IssuedAt:   time.Now(),
+		NewSession: rotatedSessionParams,
+	})
+	require.NoError(t, err)
+
+	// presenting the rotated token again blocks the whole family
+	_, err = store.RotateSessionTx(context.Background(), RotateSessionTxParams{
+		SessionID:  session1.ID,
+		IssuedAt:   time.Now(),
+		NewSession: rotatedSessionParams,
+	})
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+
+	session2, err := store.GetSession(context.Background(), result.NewSession.ID)
+	require.NoError(t, err)
+	require.True(t, session2.IsBlocked)
+
+	// and the latest session can no longer be rotated either
+	_, err = store.RotateSessionTx(context.Background(), RotateSessionTxParams{
+		SessionID:  session2.ID,
+		IssuedAt:   time.Now(),
+		NewSession: rotatedSessionParams,
+	})
+	require.ErrorIs(t, err, ErrSessionBlocked)
+}
+
+func TestBlockUserSessions(t *testing.T) {
+	user := createRandomUser(t)
+	createRandomSession(t, user.Username)
+	createRandomSession(t, user.Username)
+
+	sessions, err := testQueries.ListActiveSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+
+	err = testQueries.BlockUserSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+
+	sessions, err = testQueries.ListActiveSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+}
+
+func TestDeleteExpiredSessions(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := newSessionParams(user.Username, uuid.Nil)
+	arg.ExpiresAt = time.Now().Add(-2 * time.Hour)
+	expired, err := testQueries.CreateSession(context.Background(), arg)
+	require.NoError(t, err)
+	active := createRandomSession(t, user.Username)
+
+	deleted, err := testQueries.DeleteExpiredSessions(context.Background(), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, deleted, int64(1))
+
+	_, err = testQueries.GetSession(context.Background(), expired.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = testQueries.GetSession(context.Background(), active.ID)
+	require.NoError(t, err)
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementQueries(t *testing.T) {
+	account := createFundedAccountInCurrency(t, util.USD, 0)
+	periodStart := time.Now().Add(-time.Minute)
+
+	for _, amount := range []int64{100, -40} {
+		_, err := testQueries.CreateEntry(context.Background(), CreateEntryParams{
+			AccountID: account.ID,
+			Amount:    amount,
+		})
+		require.NoError(t, err)
+
+		_, err = testQueries.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+			ID:     account.ID,
+			Amount: amount,
+		})
+		require.NoError(t, err)
+	}
+
+	openingBalance, err := testQueries.GetAccountBalanceAt(context.Background(), GetAccountBalanceAtParams{
+		At:        periodStart,
+		AccountID: account.ID,
+	})
+	require.NoError(t, err)
+	require.Zero(t, openingBalance)
+
+	closingBalance, err := testQueries.GetAccountBalanceAt(context.Background(), GetAccountBalanceAtParams{
+		At:        time.Now().Add(time.Minute),
+		AccountID: account.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(60), closingBalance)
+
+	// a stored balance that disagrees with the ledger does not move the statement balances
+	_, err = testQueries.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+		ID:     account.ID,
+		Amount: 1000,
+	})
+	require.NoError(t, err)
+
+	closingBalance, err = testQueries.GetAccountBalanceAt(context.Background(), GetAccountBalanceAtParams{
+		At:        time.Now().Add(time.Minute),
+		AccountID: account.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(60), closingBalance)
+
+	entries, err := testQueries.ListStatementEntries(context.Background(), ListStatementEntriesParams{
+		AccountID:   account.ID,
+		PeriodStart: periodStart,
+		PeriodEnd:   time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, int64(100), entries[0].Amount)
+
+	accountIDs, err := testQueries.ListStatementAccountIDs(context.Background(), ListStatementAccountIDsParams{
+		PeriodEnd:   time.Now().Add(time.Minute),
+		PeriodStart: periodStart,
+	})
+	require.NoError(t, err)
+	require.Contains(t, accountIDs, account.ID)
+}
+
+func TestListStatementAccountIDsClosedAccount(t *testing.T) {
+	account := createFundedAccountInCurrency(t, util.USD, 0)
+	_, err := testQueries.CreateEntry(context.Background(), CreateEntryParams{
+		AccountID: account.ID,
+		Amount:    100,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID:         account.ID,
+		FromStatus: AccountStatusActive,
+		ToStatus:   AccountStatusClosed,
+	})
+	require.NoError(t, err)
+
+	// the account was closed during the period, so it still gets its final statement
+	accountIDs, err := testQueries.ListStatementAccountIDs(context.Background(), ListStatementAccountIDsParams{
+		PeriodEnd:   time.Now().Add(time.Minute),
+		PeriodStart: time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+	require.Contains(t, accountIDs, account.ID)
+
+	// no statements for the periods after the one it was closed in
+	accountIDs, err = testQueries.ListStatementAccountIDs(context.Background(), ListStatementAccountIDsParams{
+		PeriodEnd:   time.Now().Add(time.Hour),
+		PeriodStart: time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.NotContains(t, accountIDs, account.ID)
+}
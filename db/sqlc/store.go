@@ -10,8 +10,20 @@ import (
 type Store interface {
 	Querier
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	ExchangeTransferTx(ctx context.Context, arg ExchangeTransferTxParams) (ExchangeTransferTxResult, error)
+	QuoteTransferTx(ctx context.Context, arg QuoteTransferTxParams) (QuoteTransferTxResult, error)
+	ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error)
+	DepositTx(ctx context.Context, arg CashTxParams) (CashTxResult, error)
+	WithdrawTx(ctx context.Context, arg CashTxParams) (CashTxResult, error)
 	CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error)
 	VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error)
+	RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (RotateSessionTxResult, error)
+	ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error)
+	RequestPasswordResetTx(ctx context.Context, arg RequestPasswordResetTxParams) (RequestPasswordResetTxResult, error)
+	ResendVerifyEmailTx(ctx context.Context, arg ResendVerifyEmailTxParams) (ResendVerifyEmailTxResult, error)
+	UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (UpdateUserTxResult, error)
+	RelayOutboxTx(ctx context.Context, arg RelayOutboxTxParams) (RelayOutboxTxResult, error)
+	ProvisionSystemAccountsTx(ctx context.Context, arg ProvisionSystemAccountsTxParams) (ProvisionSystemAccountsTxResult, error)
 }
 
 // SQLStore provides all functions to execute SQL queries and transactions
@@ -46,3 +58,22 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 
 	return tx.Commit()
 }
+
+// dryRunTx executes a function within a database transaction that is always rolled back
+func (store *SQLStore) dryRunTx(ctx context.Context, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	q := New(tx)
+	err = fn(q)
+	if rbErr := tx.Rollback(); rbErr != nil {
+		if err != nil {
+			return fmt.Errorf("tx error: %v, rb err: %v", err, rbErr)
+		}
+		return rbErr
+	}
+
+	return err
+}
@@ -5,14 +5,16 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/google/uuid"
+	"github.com/guncv/Simple-Bank/util"
 	"github.com/stretchr/testify/require"
 )
 
 func TestTransferTx(t *testing.T) {
 	store := NewStore(testDB)
 
-	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account1 := createFundedAccount(t, 1000)
+	account2 := createFundedAccount(t, 1000)
 
 	// run n concurrent transfer transaction
 	n := 5
@@ -114,8 +116,8 @@ func TestTransferTx(t *testing.T) {
 func TestTransferTxDeadlock(t *testing.T) {
 	store := NewStore(testDB)
 
-	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account1 := createFundedAccount(t, 1000)
+	account2 := createFundedAccount(t, 1000)
 
 	// run n concurrent transfer transaction
 	n := 10
@@ -157,3 +159,252 @@ func TestTransferTxDeadlock(t *testing.T) {
 	require.Equal(t, account1.Balance, updatedAccount1.Balance)
 	require.Equal(t, account2.Balance, updatedAccount2.Balance)
 }
+
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createFundedAccount(t, 10)
+	account2 := createFundedAccount(t, 10)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + 1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// nothing should have been written
+	updatedAccount1, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	updatedAccount2, err := store.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance, updatedAccount2.Balance)
+}
+
+func TestTransferTxOverdraftLimit(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createFundedAccount(t, 10)
+	account2 := createFundedAccount(t, 10)
+
+	account1, err := store.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account1.ID,
+		OverdraftLimit: 50,
+	})
+	require.NoError(t, err)
+
+	// the balance may go negative down to the overdraft limit
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        60,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(-50), result.FromAccount.Balance)
+	require.Equal(t, int64(70), result.ToAccount.Balance)
+
+	// but not any further
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestTransferTxConcurrentOverdraft(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createFundedAccount(t, 30)
+	account2 := createFundedAccount(t, 0)
+
+	// only 3 of the n concurrent transfers can succeed
+	n := 5
+	amount := int64(10)
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        amount,
+			})
+
+			errs <- err
+		}()
+	}
+
+	failed := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err != nil {
+			require.ErrorIs(t, err, ErrInsufficientFunds)
+			failed++
+		}
+	}
+	require.Equal(t, 2, failed)
+
+	updatedAccount1, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Zero(t, updatedAccount1.Balance)
+}
+
+func TestTransferTxIdempotencyKey(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createFundedAccount(t, 100)
+	account2 := createFundedAccount(t, 100)
+
+	arg := TransferTxParams{
+		FromAccountID:  account1.ID,
+		ToAccountID:    account2.ID,
+		Amount:         10,
+		IdempotencyKey: util.RandomString(16),
+	}
+
+	result1, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, result1.Replayed)
+
+	// retrying with the same key returns the original result without moving money again
+	result2, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result2.Replayed)
+	require.Equal(t, result1.Transfer.ID, result2.Transfer.ID)
+	require.Equal(t, result1.FromAccount.Balance, result2.FromAccount.Balance)
+
+	updatedAccount1, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-arg.Amount, updatedAccount1.Balance)
+
+	// reusing the key with a different request is rejected
+	arg.Amount = 20
+	_, err = store.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrIdempotencyKeyConflict)
+
+	// so is reusing it for the same amount with a quote attached
+	arg.Amount = 10
+	arg.QuoteID = uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	_, err = store.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrIdempotencyKeyConflict)
+}
+
+// createFundedAccount creates a random account with the given balance
+func createFundedAccount(t *testing.T, balance int64) Accounts {
+	account := createRandomAccount(t)
+
+	account, err := testQueries.UpdateAccount(context.Background(), UpdateAccountParams{
+		ID:      account.ID,
+		Balance: balance,
+	})
+	require.NoError(t, err)
+
+	return account
+}
+
+func TestTransferTxAccountNotActive(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createFundedAccount(t, 100)
+	account2 := createFundedAccount(t, 100)
+
+	_, err := testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID:         account2.ID,
+		FromStatus: AccountStatusActive,
+		ToStatus:   AccountStatusFrozen,
+	})
+	require.NoError(t, err)
+
+	// frozen accounts cannot be credited
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrAccountNotActive)
+
+	// nor debited
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrAccountNotActive)
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+}
+
+func TestTransferTxToSystemAccount(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createFundedAccount(t, 100)
+	for _, owner := range []string{CashAccountOwner, FXAccountOwner, RevenueAccountOwner} {
+		systemAccount, err := testQueries.GetAccountByCurrency(context.Background(), GetAccountByCurrencyParams{
+			Owner:    owner,
+			Currency: account.Currency,
+		})
+		require.NoError(t, err)
+
+		_, err = store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: account.ID,
+			ToAccountID:   systemAccount.ID,
+			Amount:        10,
+		})
+		require.ErrorIs(t, err, ErrSystemAccount)
+	}
+
+	updatedAccount, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, updatedAccount.Balance)
+}
+
+func TestDepositAndWithdrawTx(t *testing.T) {
+	store := NewStore(testDB)
+	banker := createRandomUser(t)
+	account := createFundedAccount(t, 0)
+
+	cashAccount, err := testQueries.GetAccountByCurrency(context.Background(), GetAccountByCurrencyParams{
+		Owner:    CashAccountOwner,
+		Currency: account.Currency,
+	})
+	require.NoError(t, err)
+
+	deposit, err := store.DepositTx(context.Background(), CashTxParams{
+		AccountID:   account.ID,
+		Amount:      100,
+		InitiatedBy: banker.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, cashAccount.ID, deposit.Transfer.FromAccountID)
+	require.Equal(t, account.ID, deposit.Transfer.ToAccountID)
+	require.Equal(t, banker.Username, deposit.Transfer.InitiatedBy.String)
+	require.Equal(t, int64(100), deposit.Account.Balance)
+	require.Equal(t, int64(100), deposit.Entry.Amount)
+	require.Equal(t, int64(-100), deposit.CashEntry.Amount)
+	require.Equal(t, cashAccount.ID, deposit.CashAccount.ID)
+
+	withdraw, err := store.WithdrawTx(context.Background(), CashTxParams{
+		AccountID:   account.ID,
+		Amount:      40,
+		InitiatedBy: banker.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.ID, withdraw.Transfer.FromAccountID)
+	require.Equal(t, cashAccount.ID, withdraw.Transfer.ToAccountID)
+	require.Equal(t, int64(60), withdraw.Account.Balance)
+	require.Equal(t, int64(-40), withdraw.Entry.Amount)
+	require.Equal(t, int64(40), withdraw.CashEntry.Amount)
+
+	// the customer account cannot be overdrawn by a withdrawal
+	_, err = store.WithdrawTx(context.Background(), CashTxParams{
+		AccountID:   account.ID,
+		Amount:      61,
+		InitiatedBy: banker.Username,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
@@ -7,26 +7,41 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
     from_account_id,
     to_account_id,
-    amount
+    amount,
+    initiated_by,
+    fee,
+    reversal_of
 ) VALUES (
-    $1, $2, $3
-) RETURNING id, from_account_id, to_account_id, amount, created_at
+    $1, $2, $3, $4, $5, $6
+) RETURNING id, from_account_id, to_account_id, amount, created_at, initiated_by, fee, reversal_of
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64          `json:"from_account_id"`
+	ToAccountID   int64          `json:"to_account_id"`
+	Amount        int64          `json:"amount"`
+	InitiatedBy   sql.NullString `json:"initiated_by"`
+	Fee           int64          `json:"fee"`
+	ReversalOf    sql.NullInt64  `json:"reversal_of"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfers, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.InitiatedBy,
+		arg.Fee,
+		arg.ReversalOf,
+	)
 	var i Transfers
 	err := row.Scan(
 		&i.ID,
@@ -34,22 +49,28 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.InitiatedBy,
+		&i.Fee,
+		&i.ReversalOf,
 	)
 	return i, err
 }
 
-const deleteTransfer = `-- name: DeleteTransfer :exec
-DELETE FROM transfers 
-WHERE id = $1
+const getReversedAmount = `-- name: GetReversedAmount :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS reversed_amount
+FROM transfers
+WHERE reversal_of = $1
 `
 
-func (q *Queries) DeleteTransfer(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteTransfer, id)
-	return err
+func (q *Queries) GetReversedAmount(ctx context.Context, reversalOf sql.NullInt64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getReversedAmount, reversalOf)
+	var reversed_amount int64
+	err := row.Scan(&reversed_amount)
+	return reversed_amount, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers 
+SELECT id, from_account_id, to_account_id, amount, created_at, initiated_by, fee, reversal_of FROM transfers 
 WHERE id = $1 
 LIMIT 1
 `
@@ -63,12 +84,183 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfers, error)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.InitiatedBy,
+		&i.Fee,
+		&i.ReversalOf,
 	)
 	return i, err
 }
 
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, initiated_by, fee, reversal_of FROM transfers
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfers, error) {
+	row := q.db.QueryRowContext(ctx, getTransferForUpdate, id)
+	var i Transfers
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.InitiatedBy,
+		&i.Fee,
+		&i.ReversalOf,
+	)
+	return i, err
+}
+
+const getTransferTotals = `-- name: GetTransferTotals :one
+SELECT
+    COALESCE(SUM(t.amount) FILTER (WHERE t.created_at >= date_trunc('day', now())), 0)::bigint AS daily_total,
+    COALESCE(SUM(t.amount), 0)::bigint AS monthly_total
+FROM transfers t
+JOIN accounts a ON a.id = t.from_account_id
+WHERE
+    a.owner = $1 AND
+    a.currency = $2 AND
+    t.initiated_by IS NULL AND
+    t.created_at >= date_trunc('month', now())
+`
+
+type GetTransferTotalsParams struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+}
+
+type GetTransferTotalsRow struct {
+	DailyTotal   int64 `json:"daily_total"`
+	MonthlyTotal int64 `json:"monthly_total"`
+}
+
+func (q *Queries) GetTransferTotals(ctx context.Context, arg GetTransferTotalsParams) (GetTransferTotalsRow, error) {
+	row := q.db.QueryRowContext(ctx, getTransferTotals, arg.Owner, arg.Currency)
+	var i GetTransferTotalsRow
+	err := row.Scan(&i.DailyTotal, &i.MonthlyTotal)
+	return i, err
+}
+
+const listAccountTransfers = `-- name: ListAccountTransfers :many
+SELECT
+    t.id,
+    t.from_account_id,
+    t.to_account_id,
+    t.amount,
+    t.created_at,
+    t.initiated_by,
+    t.fee,
+    t.reversal_of,
+    c.id AS counterparty_account_id,
+    c.currency AS counterparty_currency,
+    (CASE WHEN leg.id IS NULL THEN t.amount ELSE leg.amount END)::bigint AS counterparty_amount,
+    x.rate AS exchange_rate
+FROM transfers t
+-- an exchange leg is shown against the customer on the other side instead of the FX account
+LEFT JOIN exchanges x ON
+    (x.debit_transfer_id = t.id AND t.from_account_id = $1) OR
+    (x.credit_transfer_id = t.id AND t.to_account_id = $1)
+LEFT JOIN transfers leg ON
+    leg.id = CASE WHEN x.debit_transfer_id = t.id THEN x.credit_transfer_id ELSE x.debit_transfer_id END
+JOIN accounts c ON c.id = CASE
+    WHEN x.debit_transfer_id = t.id THEN leg.to_account_id
+    WHEN x.credit_transfer_id = t.id THEN leg.from_account_id
+    WHEN t.from_account_id = $1 THEN t.to_account_id
+    ELSE t.from_account_id
+END
+WHERE
+    (
+        ($2::text <> 'incoming' AND t.from_account_id = $1) OR
+        ($2 <> 'outgoing' AND t.to_account_id = $1)
+    ) AND
+    ($3::bigint IS NULL OR c.id = $3) AND
+    ($4::bigint IS NULL OR t.id < $4) AND
+    ($5::bigint IS NULL OR t.amount >= $5) AND
+    ($6::bigint IS NULL OR t.amount <= $6) AND
+    ($7::timestamptz IS NULL OR t.created_at >= $7) AND
+    ($8::timestamptz IS NULL OR t.created_at < $8)
+ORDER BY t.id DESC
+LIMIT $9
+`
+
+type ListAccountTransfersParams struct {
+	AccountID      int64         `json:"account_id"`
+	Direction      string        `json:"direction"`
+	CounterpartyID sql.NullInt64 `json:"counterparty_id"`
+	BeforeID       sql.NullInt64 `json:"before_id"`
+	MinAmount      sql.NullInt64 `json:"min_amount"`
+	MaxAmount      sql.NullInt64 `json:"max_amount"`
+	FromTime       sql.NullTime  `json:"from_time"`
+	ToTime         sql.NullTime  `json:"to_time"`
+	PageSize       int32         `json:"page_size"`
+}
+
+type ListAccountTransfersRow struct {
+	ID                    int64          `json:"id"`
+	FromAccountID         int64          `json:"from_account_id"`
+	ToAccountID           int64          `json:"to_account_id"`
+	Amount                int64          `json:"amount"`
+	CreatedAt             time.Time      `json:"created_at"`
+	InitiatedBy           sql.NullString `json:"initiated_by"`
+	Fee                   int64          `json:"fee"`
+	ReversalOf            sql.NullInt64  `json:"reversal_of"`
+	CounterpartyAccountID int64          `json:"counterparty_account_id"`
+	CounterpartyCurrency  string         `json:"counterparty_currency"`
+	CounterpartyAmount    int64          `json:"counterparty_amount"`
+	ExchangeRate          sql.NullString `json:"exchange_rate"`
+}
+
+func (q *Queries) ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]ListAccountTransfersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountTransfers,
+		arg.AccountID,
+		arg.Direction,
+		arg.CounterpartyID,
+		arg.BeforeID,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.FromTime,
+		arg.ToTime,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAccountTransfersRow{}
+	for rows.Next() {
+		var i ListAccountTransfersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.InitiatedBy,
+			&i.Fee,
+			&i.ReversalOf,
+			&i.CounterpartyAccountID,
+			&i.CounterpartyCurrency,
+			&i.CounterpartyAmount,
+			&i.ExchangeRate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTransfer = `-- name: ListTransfer :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers 
+SELECT id, from_account_id, to_account_id, amount, created_at, initiated_by, fee, reversal_of FROM transfers 
 ORDER BY id 
 LIMIT $1 
 OFFSET $2
@@ -94,6 +286,9 @@ func (q *Queries) ListTransfer(ctx context.Context, arg ListTransferParams) ([]T
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.InitiatedBy,
+			&i.Fee,
+			&i.ReversalOf,
 		); err != nil {
 			return nil, err
 		}
@@ -107,28 +302,3 @@ func (q *Queries) ListTransfer(ctx context.Context, arg ListTransferParams) ([]T
 	}
 	return items, nil
 }
-
-const updateTransfer = `-- name: UpdateTransfer :one
-UPDATE transfers 
-SET amount = $2 
-WHERE id = $1 
-RETURNING id, from_account_id, to_account_id, amount, created_at
-`
-
-type UpdateTransferParams struct {
-	ID     int64 `json:"id"`
-	Amount int64 `json:"amount"`
-}
-
-func (q *Queries) UpdateTransfer(ctx context.Context, arg UpdateTransferParams) (Transfers, error) {
-	row := q.db.QueryRowContext(ctx, updateTransfer, arg.ID, arg.Amount)
-	var i Transfers
-	err := row.Scan(
-		&i.ID,
-		&i.FromAccountID,
-		&i.ToAccountID,
-		&i.Amount,
-		&i.CreatedAt,
-	)
-	return i, err
-}
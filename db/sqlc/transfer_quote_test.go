@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	fromAccount := createFundedAccountInCurrency(t, util.USD, 100)
+	toAccount := createFundedAccountInCurrency(t, util.USD, 0)
+
+	result, err := store.QuoteTransferTx(context.Background(), QuoteTransferTxParams{
+		Username:      fromAccount.Owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        40,
+		ExpiresAt:     time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.NoError(t, result.Rejection)
+	require.Equal(t, int64(60), result.FromAccount.Balance)
+	require.Equal(t, int64(40), result.ConvertedAmount)
+	require.NotEqual(t, uuid.Nil, result.Quote.ID)
+
+	// the dry run is rolled back
+	account, err := testQueries.GetAccount(context.Background(), fromAccount.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), account.Balance)
+
+	// a quote only matches the transfer it was issued for
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        50,
+		QuoteID:       uuid.NullUUID{UUID: result.Quote.ID, Valid: true},
+	})
+	require.ErrorIs(t, err, ErrQuoteMismatch)
+
+	transfer, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        40,
+		QuoteID:       uuid.NullUUID{UUID: result.Quote.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(60), transfer.FromAccount.Balance)
+
+	// a quote can only be executed once
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        40,
+		QuoteID:       uuid.NullUUID{UUID: result.Quote.ID, Valid: true},
+	})
+	require.ErrorIs(t, err, ErrQuoteNotFound)
+
+	rejected, err := store.QuoteTransferTx(context.Background(), QuoteTransferTxParams{
+		Username:      fromAccount.Owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        1000,
+		ExpiresAt:     time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.ErrorIs(t, rejected.Rejection, ErrInsufficientFunds)
+	require.Equal(t, uuid.Nil, rejected.Quote.ID)
+}
+
+func TestQuoteTransferTxExpired(t *testing.T) {
+	store := NewStore(testDB)
+
+	fromAccount := createFundedAccountInCurrency(t, util.EUR, 100)
+	toAccount := createFundedAccountInCurrency(t, util.EUR, 0)
+
+	result, err := store.QuoteTransferTx(context.Background(), QuoteTransferTxParams{
+		Username:      fromAccount.Owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        10,
+		ExpiresAt:     time.Now().Add(-time.Second),
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        10,
+		QuoteID:       uuid.NullUUID{UUID: result.Quote.ID, Valid: true},
+	})
+	require.ErrorIs(t, err, ErrQuoteNotFound)
+}
@@ -52,36 +52,6 @@ func TestGetTransfer(t *testing.T) {
 	require.WithinDuration(t, transfer1.CreatedAt, transfer2.CreatedAt, time.Second)
 }
 
-func TestUpdateTranfer(t *testing.T) {
-	transfer1 := createRandomTransfer(t)
-
-	arg := UpdateTransferParams{
-		ID:     transfer1.ID,
-		Amount: util.RandomMoney(),
-	}
-
-	transfer2, err := testQueries.UpdateTransfer(context.Background(), arg)
-	require.NoError(t, err)
-	require.NotEmpty(t, transfer2)
-
-	require.Equal(t, transfer1.ID, transfer2.ID)
-	require.Equal(t, transfer1.FromAccountID, transfer2.FromAccountID)
-	require.NotEqual(t, transfer1.Amount, transfer2.Amount)
-	require.Equal(t, transfer1.ToAccountID, transfer2.ToAccountID)
-	require.WithinDuration(t, transfer1.CreatedAt, transfer2.CreatedAt, time.Second)
-}
-
-func TestDeleteTransfer(t *testing.T) {
-	transfer1 := createRandomTransfer(t)
-	err := testQueries.DeleteTransfer(context.Background(), transfer1.ID)
-	require.NoError(t, err)
-
-	transfer2, err := testQueries.GetTransfer(context.Background(), transfer1.ID)
-	require.Error(t, err)
-	require.EqualError(t, err, sql.ErrNoRows.Error())
-	require.Empty(t, transfer2)
-}
-
 func TestListTransfer(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		createRandomTransfer(t)
@@ -100,3 +70,144 @@ func TestListTransfer(t *testing.T) {
 		require.NotEmpty(t, transfer)
 	}
 }
+
+func TestListAccountTransfers(t *testing.T) {
+	account := createFundedAccountInCurrency(t, util.USD, 0)
+	other1 := createFundedAccountInCurrency(t, util.USD, 0)
+	other2 := createFundedAccountInCurrency(t, util.USD, 0)
+
+	var transfers []Transfers
+	for _, arg := range []CreateTransferParams{
+		{FromAccountID: account.ID, ToAccountID: other1.ID, Amount: 10},
+		{FromAccountID: other1.ID, ToAccountID: account.ID, Amount: 20},
+		{FromAccountID: account.ID, ToAccountID: other2.ID, Amount: 30},
+		{FromAccountID: other2.ID, ToAccountID: account.ID, Amount: 40},
+		{FromAccountID: other1.ID, ToAccountID: other2.ID, Amount: 50},
+	} {
+		transfer, err := testQueries.CreateTransfer(context.Background(), arg)
+		require.NoError(t, err)
+		transfers = append(transfers, transfer)
+	}
+
+	amounts := func(transfers []ListAccountTransfersRow) []int64 {
+		var amounts []int64
+		for _, transfer := range transfers {
+			amounts = append(amounts, transfer.Amount)
+		}
+		return amounts
+	}
+
+	testCases := []struct {
+		name     string
+		arg      ListAccountTransfersParams
+		expected []int64
+	}{
+		{
+			name:     "Both",
+			arg:      ListAccountTransfersParams{AccountID: account.ID, PageSize: 10},
+			expected: []int64{40, 30, 20, 10},
+		},
+		{
+			name:     "Outgoing",
+			arg:      ListAccountTransfersParams{AccountID: account.ID, Direction: util.OutgoingDirection, PageSize: 10},
+			expected: []int64{30, 10},
+		},
+		{
+			name: "IncomingFromCounterparty",
+			arg: ListAccountTransfersParams{
+				AccountID:      account.ID,
+				Direction:      util.IncomingDirection,
+				CounterpartyID: sql.NullInt64{Int64: other2.ID, Valid: true},
+				PageSize:       10,
+			},
+			expected: []int64{40},
+		},
+		{
+			name: "AmountRange",
+			arg: ListAccountTransfersParams{
+				AccountID: account.ID,
+				MinAmount: sql.NullInt64{Int64: 15, Valid: true},
+				MaxAmount: sql.NullInt64{Int64: 35, Valid: true},
+				PageSize:  10,
+			},
+			expected: []int64{30, 20},
+		},
+		{
+			name: "NextPage",
+			arg: ListAccountTransfersParams{
+				AccountID: account.ID,
+				BeforeID:  sql.NullInt64{Int64: transfers[2].ID, Valid: true},
+				PageSize:  1,
+			},
+			expected: []int64{20},
+		},
+		{
+			name: "FutureTime",
+			arg: ListAccountTransfersParams{
+				AccountID: account.ID,
+				FromTime:  sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true},
+				PageSize:  10,
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := testQueries.ListAccountTransfers(context.Background(), tc.arg)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, amounts(result))
+		})
+	}
+}
+
+func TestListAccountTransfersExchange(t *testing.T) {
+	store := NewStore(testDB)
+	banker := createRandomUser(t)
+
+	_, err := testQueries.CreateExchangeRate(context.Background(), CreateExchangeRateParams{
+		FromCurrency: util.USD,
+		ToCurrency:   util.EUR,
+		Rate:         "0.5",
+		EffectiveAt:  time.Now().Add(-time.Second),
+		CreatedBy:    banker.Username,
+	})
+	require.NoError(t, err)
+
+	fromAccount := createFundedAccountInCurrency(t, util.USD, 1000)
+	toAccount := createFundedAccountInCurrency(t, util.EUR, 0)
+
+	exchange, err := store.ExchangeTransferTx(context.Background(), ExchangeTransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        100,
+	})
+	require.NoError(t, err)
+
+	// the debit leg shows the recipient and the amount it received
+	sent, err := testQueries.ListAccountTransfers(context.Background(), ListAccountTransfersParams{
+		AccountID:      fromAccount.ID,
+		CounterpartyID: sql.NullInt64{Int64: toAccount.ID, Valid: true},
+		PageSize:       10,
+	})
+	require.NoError(t, err)
+	require.Len(t, sent, 1)
+	require.Equal(t, exchange.DebitTransfer.ID, sent[0].ID)
+	require.Equal(t, toAccount.ID, sent[0].CounterpartyAccountID)
+	require.Equal(t, util.EUR, sent[0].CounterpartyCurrency)
+	require.Equal(t, int64(50), sent[0].CounterpartyAmount)
+	require.Equal(t, exchange.Exchange.Rate, sent[0].ExchangeRate.String)
+
+	// the credit leg shows the sender and the amount it paid
+	received, err := testQueries.ListAccountTransfers(context.Background(), ListAccountTransfersParams{
+		AccountID: toAccount.ID,
+		PageSize:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, received, 1)
+	require.Equal(t, exchange.CreditTransfer.ID, received[0].ID)
+	require.Equal(t, fromAccount.ID, received[0].CounterpartyAccountID)
+	require.Equal(t, util.USD, received[0].CounterpartyCurrency)
+	require.Equal(t, int64(100), received[0].CounterpartyAmount)
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// CashAccountOwner is the system user owning the cash account of every currency
+const CashAccountOwner = "simplebank_cash"
+
+// CashTxParams contains the input parameters of the deposit and withdraw transactions
+type CashTxParams struct {
+	AccountID   int64  `json:"account_id"`
+	Amount      int64  `json:"amount"`
+	InitiatedBy string `json:"initiated_by"`
+}
+
+// CashTxResult is the result of the deposit and withdraw transactions
+type CashTxResult struct {
+	Transfer    Transfers `json:"transfer"`
+	Account     Accounts  `json:"account"`
+	CashAccount Accounts  `json:"cash_account"`
+	Entry       Entries   `json:"entry"`
+	CashEntry   Entries   `json:"cash_entry"`
+}
+
+// DepositTx credits an account with cash, booking the opposite entry against the cash account of its currency
+func (store *SQLStore) DepositTx(ctx context.Context, arg CashTxParams) (CashTxResult, error) {
+	return store.cashTx(ctx, arg, true)
+}
+
+// WithdrawTx debits cash from an account, booking the opposite entry against the cash account of its currency
+func (store *SQLStore) WithdrawTx(ctx context.Context, arg CashTxParams) (CashTxResult, error) {
+	return store.cashTx(ctx, arg, false)
+}
+
+func (store *SQLStore) cashTx(ctx context.Context, arg CashTxParams, deposit bool) (CashTxResult, error) {
+	var result CashTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		cashAccount, err := q.GetAccountByCurrency(ctx, GetAccountByCurrencyParams{
+			Owner:    CashAccountOwner,
+			Currency: account.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Money flows from the cash account on deposit and into it on withdrawal
+		fromAccountID, toAccountID := cashAccount.ID, account.ID
+		if !deposit {
+			fromAccountID, toAccountID = account.ID, cashAccount.ID
+		}
+
+		fromAccount, toAccount, err := lockAccounts(ctx, q, fromAccountID, toAccountID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkAccountActive(fromAccount); err != nil {
+			return err
+		}
+		if err := checkAccountActive(toAccount); err != nil {
+			return err
+		}
+
+		// The cash account has no floor, only the customer account is checked on withdrawal
+		if !deposit && fromAccount.Balance-arg.Amount < -fromAccount.OverdraftLimit {
+			return ErrInsufficientFunds
+		}
+
+		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+			FromAccountID: fromAccountID,
+			ToAccountID:   toAccountID,
+			Amount:        arg.Amount,
+			InitiatedBy: sql.NullString{
+				String: arg.InitiatedBy,
+				Valid:  true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		fromEntry, err := q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: fromAccountID,
+			Amount:    -arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		toEntry, err := q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: toAccountID,
+			Amount:    arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		if fromAccountID < toAccountID {
+			fromAccount, toAccount, err = addMoney(ctx, q, fromAccountID, toAccountID, arg.Amount)
+		} else {
+			toAccount, fromAccount, err = addMoney(ctx, q, toAccountID, fromAccountID, -arg.Amount)
+		}
+		if err != nil {
+			return err
+		}
+
+		if deposit {
+			result.CashAccount, result.Account = fromAccount, toAccount
+			result.CashEntry, result.Entry = fromEntry, toEntry
+		} else {
+			result.Account, result.CashAccount = fromAccount, toAccount
+			result.Entry, result.CashEntry = fromEntry, toEntry
+		}
+
+		return nil
+	})
+
+	return result, err
+}
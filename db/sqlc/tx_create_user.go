@@ -7,7 +7,8 @@ import (
 // CreateUserTxParams contains the input parameters of the create user transaction
 type CreateUserTxParams struct {
 	CreateUserParams
-	AfterCreate func(user Users) error
+	// AfterCreate builds the task written to the outbox with the new user
+	AfterCreate func(user Users) (CreateOutboxMessageParams, error)
 }
 
 // CreateUserTxResult is the result of the create user transaction
@@ -33,7 +34,13 @@ func (store *SQLStore) CreateUserTx(ctx context.Context, arg CreateUserTxParams)
 			return err
 		}
 
-		return arg.AfterCreate(result.User)
+		message, err := arg.AfterCreate(result.User)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateOutboxMessage(ctx, message)
+		return err
 	})
 
 	return result, err
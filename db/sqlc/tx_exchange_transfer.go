@@ -0,0 +1,327 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/guncv/Simple-Bank/util"
+)
+
+// FXAccountOwner is the system user owning the FX account of every currency
+const FXAccountOwner = "simplebank_fx"
+
+// ExchangeTransferTxParams contains the input parameters of the exchange transfer transaction
+type ExchangeTransferTxParams struct {
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID   int64 `json:"to_account_id"`
+	// Amount is in the currency of the source account
+	Amount int64 `json:"amount"`
+	// IdempotencyKey is optional; when set, retries with the same key replay the original result
+	IdempotencyKey string `json:"idempotency_key"`
+	// QuoteID is optional; when set, the quote is consumed and its exchange rate and fee are applied
+	QuoteID uuid.NullUUID `json:"quote_id"`
+	// Currencies provides the minor units used to convert the amount
+	Currencies util.Currencies `json:"-"`
+}
+
+// ExchangeTransferTxResult is the result of the exchange transfer transaction
+type ExchangeTransferTxResult struct {
+	Exchange Exchanges `json:"exchange"`
+	// DebitTransfer moves Amount from the source account to the FX account of its currency and carries the fee
+	DebitTransfer Transfers `json:"debit_transfer"`
+	// CreditTransfer moves ConvertedAmount from the FX account of the target currency to the target account
+	CreditTransfer Transfers `json:"credit_transfer"`
+	FromAccount    Accounts  `json:"from_account"`
+	ToAccount      Accounts  `json:"to_account"`
+	FromEntry      Entries   `json:"from_entry"`
+	ToEntry        Entries   `json:"to_entry"`
+	// FeeEntry credits the revenue account of the source currency and is empty when no fee was charged
+	FeeEntry        Entries `json:"fee_entry"`
+	Rate            string  `json:"rate"`
+	ConvertedAmount int64   `json:"converted_amount"`
+	// Replayed is true when the result was returned from a previously stored idempotency key
+	Replayed bool `json:"-"`
+}
+
+// ExchangeTransferTx moves money between accounts of different currencies at the current exchange rate
+func (store *SQLStore) ExchangeTransferTx(ctx context.Context, arg ExchangeTransferTxParams) (ExchangeTransferTxResult, error) {
+	var result ExchangeTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = runExchangeTransfer(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+// runExchangeTransfer holds the logic of ExchangeTransferTx so that it can also be dry-run by QuoteTransferTx
+func runExchangeTransfer(ctx context.Context, q *Queries, arg ExchangeTransferTxParams) (ExchangeTransferTxResult, error) {
+	var result ExchangeTransferTxResult
+
+	fromAccount, err := q.GetAccount(ctx, arg.FromAccountID)
+	if err != nil {
+		return result, err
+	}
+	toAccount, err := q.GetAccount(ctx, arg.ToAccountID)
+	if err != nil {
+		return result, err
+	}
+
+	if fromAccount.Currency == toAccount.Currency {
+		return result, ErrSameCurrency
+	}
+
+	// Claim the key before the quote is consumed, so that a retry replays instead of failing on the used quote
+	if arg.IdempotencyKey != "" {
+		claimed, err := claimIdempotencyKey(ctx, q, fromAccount.Owner, arg.IdempotencyKey, arg.requestHash(), &result)
+		if err != nil {
+			return result, err
+		}
+		if !claimed {
+			result.Replayed = true
+			return result, nil
+		}
+	}
+
+	var quote TransferQuotes
+	if arg.QuoteID.Valid {
+		quote, err = useQuote(ctx, q, arg.QuoteID.UUID, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+		if err != nil {
+			return result, err
+		}
+		if !quote.ExchangeRateID.Valid {
+			return result, fmt.Errorf("%w: quote is for a same currency transfer", ErrQuoteMismatch)
+		}
+	}
+
+	rate, err := exchangeRate(ctx, q, quote, fromAccount.Currency, toAccount.Currency)
+	if err != nil {
+		return result, err
+	}
+
+	result.Rate = rate.Rate
+	result.ConvertedAmount, err = convertAmount(
+		arg.Amount,
+		rate.Rate,
+		arg.Currencies.MinorUnit(fromAccount.Currency),
+		arg.Currencies.MinorUnit(toAccount.Currency),
+	)
+	if err != nil {
+		return result, err
+	}
+
+	fxFromAccount, err := q.GetAccountByCurrency(ctx, GetAccountByCurrencyParams{
+		Owner:    FXAccountOwner,
+		Currency: fromAccount.Currency,
+	})
+	if err != nil {
+		return result, err
+	}
+	fxToAccount, err := q.GetAccountByCurrency(ctx, GetAccountByCurrencyParams{
+		Owner:    FXAccountOwner,
+		Currency: toAccount.Currency,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	locked, err := lockAccountsByID(ctx, q, fromAccount.ID, toAccount.ID, fxFromAccount.ID, fxToAccount.ID)
+	if err != nil {
+		return result, err
+	}
+
+	for _, account := range locked {
+		if err := checkAccountActive(account); err != nil {
+			return result, err
+		}
+	}
+	if err := checkCustomerAccount(ctx, q, toAccount); err != nil {
+		return result, err
+	}
+
+	// Lock the owner so that concurrent transfers are counted against the same limits
+	owner, err := q.GetUserForUpdate(ctx, fromAccount.Owner)
+	if err != nil {
+		return result, err
+	}
+
+	if err := checkTransferLimit(ctx, q, owner, fromAccount.Currency, arg.Amount); err != nil {
+		return result, err
+	}
+
+	// The fee is charged in the source currency on the debit leg, like a same currency transfer
+	fee := quote.Fee
+	if !arg.QuoteID.Valid {
+		fee, err = transferFee(ctx, q, fromAccount.Currency, owner.Role, arg.Amount)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	// Only the source account has a floor, FX accounts absorb the position
+	fromAccount = locked[fromAccount.ID]
+	if fromAccount.Balance-arg.Amount-fee < -fromAccount.OverdraftLimit {
+		return result, ErrInsufficientFunds
+	}
+
+	result.DebitTransfer, result.FromEntry, _, err = bookTransfer(ctx, q, fromAccount.ID, fxFromAccount.ID, arg.Amount, fee)
+	if err != nil {
+		return result, err
+	}
+
+	result.CreditTransfer, _, result.ToEntry, err = bookTransfer(ctx, q, fxToAccount.ID, toAccount.ID, result.ConvertedAmount, 0)
+	if err != nil {
+		return result, err
+	}
+
+	if fee > 0 {
+		result.FeeEntry, _, err = chargeFee(ctx, q, fromAccount, fee)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	result.Exchange, err = q.CreateExchange(ctx, CreateExchangeParams{
+		DebitTransferID:  result.DebitTransfer.ID,
+		CreditTransferID: result.CreditTransfer.ID,
+		ExchangeRateID:   rate.ID,
+		Rate:             rate.Rate,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.FromAccount, err = q.GetAccount(ctx, fromAccount.ID)
+	if err != nil {
+		return result, err
+	}
+	result.ToAccount, err = q.GetAccount(ctx, toAccount.ID)
+	if err != nil {
+		return result, err
+	}
+
+	// Store the result so that retries with the same key return it
+	if arg.IdempotencyKey != "" {
+		if err := storeIdempotencyResponse(ctx, q, fromAccount.Owner, arg.IdempotencyKey, result); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// requestHash fingerprints the exchange transfer so that a reused idempotency key with a different body can be detected.
+// The prefix keeps it from matching a same currency transfer between the same accounts.
+func (arg ExchangeTransferTxParams) requestHash() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("exchange:%d:%d:%d:%s", arg.FromAccountID, arg.ToAccountID, arg.Amount, quoteKey(arg.QuoteID))))
+	return hex.EncodeToString(sum[:])
+}
+
+// exchangeRate returns the rate guaranteed by the quote, or the rate currently in effect for the currency pair
+func exchangeRate(ctx context.Context, q *Queries, quote TransferQuotes, fromCurrency string, toCurrency string) (ExchangeRates, error) {
+	if quote.ExchangeRateID.Valid {
+		return q.GetExchangeRate(ctx, quote.ExchangeRateID.Int64)
+	}
+
+	rate, err := q.GetCurrentExchangeRate(ctx, GetCurrentExchangeRateParams{
+		FromCurrency: fromCurrency,
+		ToCurrency:   toCurrency,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return rate, fmt.Errorf("%w: %s to %s", ErrExchangeRateNotFound, fromCurrency, toCurrency)
+		}
+		return rate, err
+	}
+	return rate, nil
+}
+
+// bookTransfer records a transfer with its two entries and updates both balances. The accounts must already be locked.
+// The fee is recorded on the transfer and debited with the amount; the caller moves it with chargeFee.
+func bookTransfer(ctx context.Context, q *Queries, fromAccountID int64, toAccountID int64, amount int64, fee int64) (transfer Transfers, fromEntry Entries, toEntry Entries, err error) {
+	transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID: fromAccountID,
+		ToAccountID:   toAccountID,
+		Amount:        amount,
+		Fee:           fee,
+	})
+	if err != nil {
+		return
+	}
+
+	fromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: fromAccountID,
+		Amount:    -amount - fee,
+	})
+	if err != nil {
+		return
+	}
+
+	toEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: toAccountID,
+		Amount:    amount,
+	})
+	if err != nil {
+		return
+	}
+
+	if fromAccountID < toAccountID {
+		_, _, err = addMoney(ctx, q, fromAccountID, toAccountID, amount)
+	} else {
+		_, _, err = addMoney(ctx, q, toAccountID, fromAccountID, -amount)
+	}
+	return
+}
+
+// lockAccountsByID locks the accounts with FOR NO KEY UPDATE in ascending ID order
+func lockAccountsByID(ctx context.Context, q *Queries, ids ...int64) (map[int64]Accounts, error) {
+	sorted := append([]int64(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	locked := make(map[int64]Accounts, len(sorted))
+	for _, id := range sorted {
+		if _, ok := locked[id]; ok {
+			continue
+		}
+
+		account, err := q.GetAccountForUpdate(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		locked[id] = account
+	}
+	return locked, nil
+}
+
+// convertAmount applies a decimal exchange rate to an amount, rounding down to the smallest unit.
+// The rate is quoted per major unit, so the result is rescaled when the minor units differ.
+func convertAmount(amount int64, rate string, fromMinorUnit, toMinorUnit int) (int64, error) {
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || r.Sign() <= 0 {
+		return 0, fmt.Errorf("invalid exchange rate %q", rate)
+	}
+
+	scale := new(big.Rat).SetFrac(
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(toMinorUnit)), nil),
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(fromMinorUnit)), nil),
+	)
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), r)
+	product.Mul(product, scale)
+	converted := new(big.Int).Quo(product.Num(), product.Denom())
+	if !converted.IsInt64() {
+		return 0, fmt.Errorf("converted amount overflows: %s", converted)
+	}
+	if converted.Sign() <= 0 {
+		return 0, ErrAmountTooSmall
+	}
+	return converted.Int64(), nil
+}
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+)
+
+// ProvisionSystemAccountsTxParams contains the input parameters of the provision system accounts transaction
+type ProvisionSystemAccountsTxParams struct {
+	Currencies []string
+}
+
+// ProvisionSystemAccountsTxResult is the result of the provision system accounts transaction
+type ProvisionSystemAccountsTxResult struct {
+	Created int64 `json:"created"`
+}
+
+// systemAccountOwners are the system users that need an account in every supported currency
+var systemAccountOwners = []string{CashAccountOwner, FXAccountOwner, RevenueAccountOwner}
+
+// ProvisionSystemAccountsTx opens the cash, FX and revenue accounts of every currency that does not
+// have them yet, so a currency added to the configuration can be used without a migration.
+func (store *SQLStore) ProvisionSystemAccountsTx(ctx context.Context, arg ProvisionSystemAccountsTxParams) (ProvisionSystemAccountsTxResult, error) {
+	var result ProvisionSystemAccountsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		for _, owner := range systemAccountOwners {
+			for _, currency := range arg.Currencies {
+				created, err := q.CreateSystemAccount(ctx, CreateSystemAccountParams{
+					Owner:    owner,
+					Currency: currency,
+				})
+				if err != nil {
+					return err
+				}
+				result.Created += created
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guncv/Simple-Bank/util"
+)
+
+// quoteRejections are the transfer errors reported back in a quote instead of failing it
+var quoteRejections = []error{
+	ErrInsufficientFunds,
+	ErrAccountNotActive,
+	ErrTransferLimitExceeded,
+	ErrExchangeRateNotFound,
+	ErrAmountTooSmall,
+}
+
+// QuoteTransferTxParams contains the input parameters of the quote transfer transaction
+type QuoteTransferTxParams struct {
+	Username      string    `json:"username"`
+	FromAccountID int64     `json:"from_account_id"`
+	ToAccountID   int64     `json:"to_account_id"`
+	Amount        int64     `json:"amount"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	// Currencies provides the minor units used to convert the amount of an exchange transfer
+	Currencies util.Currencies `json:"-"`
+}
+
+// QuoteTransferTxResult is the result of the quote transfer transaction
+type QuoteTransferTxResult struct {
+	// Quote is only stored when the transfer can be executed
+	Quote TransferQuotes `json:"quote"`
+	// FromAccount and ToAccount hold the balances the transfer would result in
+	FromAccount     Accounts `json:"from_account"`
+	ToAccount       Accounts `json:"to_account"`
+	Fee             int64    `json:"fee"`
+	Rate            string   `json:"rate"`
+	ConvertedAmount int64    `json:"converted_amount"`
+	// Rejection explains why the transfer would fail, e.g. ErrInsufficientFunds
+	Rejection error `json:"-"`
+}
+
+// QuoteTransferTx runs the transfer logic in a transaction that is rolled back, then stores a quote
+// that can be executed by TransferTx or ExchangeTransferTx until it expires.
+// Accounts of different currencies are quoted as an exchange transfer.
+func (store *SQLStore) QuoteTransferTx(ctx context.Context, arg QuoteTransferTxParams) (QuoteTransferTxResult, error) {
+	var result QuoteTransferTxResult
+	var exchangeRateID sql.NullInt64
+
+	err := store.dryRunTx(ctx, func(q *Queries) error {
+		fromAccount, err := q.GetAccount(ctx, arg.FromAccountID)
+		if err != nil {
+			return err
+		}
+		toAccount, err := q.GetAccount(ctx, arg.ToAccountID)
+		if err != nil {
+			return err
+		}
+
+		if fromAccount.Currency == toAccount.Currency {
+			transfer, err := runTransfer(ctx, q, TransferTxParams{
+				FromAccountID: arg.FromAccountID,
+				ToAccountID:   arg.ToAccountID,
+				Amount:        arg.Amount,
+			})
+			if err != nil {
+				return err
+			}
+
+			result.FromAccount = transfer.FromAccount
+			result.ToAccount = transfer.ToAccount
+			result.Fee = transfer.Transfer.Fee
+			result.ConvertedAmount = arg.Amount
+			return nil
+		}
+
+		exchange, err := runExchangeTransfer(ctx, q, ExchangeTransferTxParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   arg.ToAccountID,
+			Amount:        arg.Amount,
+			Currencies:    arg.Currencies,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.FromAccount = exchange.FromAccount
+		result.ToAccount = exchange.ToAccount
+		result.Fee = exchange.DebitTransfer.Fee
+		result.Rate = exchange.Rate
+		result.ConvertedAmount = exchange.ConvertedAmount
+		exchangeRateID = sql.NullInt64{Int64: exchange.Exchange.ExchangeRateID, Valid: true}
+		return nil
+	})
+	if err != nil {
+		for _, rejection := range quoteRejections {
+			if errors.Is(err, rejection) {
+				result.Rejection = err
+				return result, nil
+			}
+		}
+		return result, err
+	}
+
+	result.Quote, err = store.CreateTransferQuote(ctx, CreateTransferQuoteParams{
+		ID:              uuid.New(),
+		Username:        arg.Username,
+		FromAccountID:   arg.FromAccountID,
+		ToAccountID:     arg.ToAccountID,
+		Amount:          arg.Amount,
+		Fee:             result.Fee,
+		ExchangeRateID:  exchangeRateID,
+		ConvertedAmount: result.ConvertedAmount,
+		ExpiresAt:       arg.ExpiresAt,
+	})
+	return result, err
+}
+
+// useQuote consumes a quote, checking that it was issued for the same transfer
+func useQuote(ctx context.Context, q *Queries, id uuid.UUID, fromAccountID int64, toAccountID int64, amount int64) (TransferQuotes, error) {
+	quote, err := q.UseTransferQuote(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return quote, ErrQuoteNotFound
+		}
+		return quote, err
+	}
+
+	if quote.FromAccountID != fromAccountID || quote.ToAccountID != toAccountID || quote.Amount != amount {
+		return quote, ErrQuoteMismatch
+	}
+	return quote, nil
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// RelayOutboxTxParams contains the input parameters of the relay outbox transaction
+type RelayOutboxTxParams struct {
+	Limit int32
+	// PublishTimeout bounds every Publish call so that a stalled broker cannot keep the batch locked, zero means no limit
+	PublishTimeout time.Duration
+	Publish        func(ctx context.Context, message Outbox) error
+}
+
+// RelayOutboxTxResult is the result of the relay outbox transaction
+type RelayOutboxTxResult struct {
+	Sent   []Outbox `json:"sent"`
+	Failed []Outbox `json:"failed"`
+}
+
+// RelayOutboxTx publishes a batch of pending outbox messages and marks them sent. Rows locked by
+// another relay are skipped. A message is published again if the transaction fails to commit after
+// Publish succeeded, so delivery is at least once. A failed publish is recorded and retried later.
+func (store *SQLStore) RelayOutboxTx(ctx context.Context, arg RelayOutboxTxParams) (RelayOutboxTxResult, error) {
+	var result RelayOutboxTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		messages, err := q.ListPendingOutboxMessages(ctx, arg.Limit)
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			if publishErr := publishOutboxMessage(ctx, arg, message); publishErr != nil {
+				err = q.MarkOutboxMessageFailed(ctx, MarkOutboxMessageFailedParams{
+					ID:        message.ID,
+					LastError: publishErr.Error(),
+				})
+				if err != nil {
+					return err
+				}
+				result.Failed = append(result.Failed, message)
+				continue
+			}
+
+			if err = q.MarkOutboxMessageSent(ctx, message.ID); err != nil {
+				return err
+			}
+			result.Sent = append(result.Sent, message)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// publishOutboxMessage calls Publish with its own deadline
+func publishOutboxMessage(ctx context.Context, arg RelayOutboxTxParams, message Outbox) error {
+	if arg.PublishTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, arg.PublishTimeout)
+		defer cancel()
+	}
+	return arg.Publish(ctx, message)
+}
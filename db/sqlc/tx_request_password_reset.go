@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// RequestPasswordResetTxParams contains the input parameters of the request password reset transaction
+type RequestPasswordResetTxParams struct {
+	Username   string `json:"username"`
+	SecretCode string `json:"secret_code"`
+	// Cooldown is the minimum time before another code is issued while the latest one is unused
+	Cooldown time.Duration `json:"cooldown"`
+	// AfterCreate builds the task written to the outbox with the new code
+	AfterCreate func(passwordReset PasswordResets) (CreateOutboxMessageParams, error)
+}
+
+// RequestPasswordResetTxResult is the result of the request password reset transaction
+type RequestPasswordResetTxResult struct {
+	PasswordReset PasswordResets `json:"password_reset"`
+}
+
+// RequestPasswordResetTx issues a password reset code for the current email of the user and
+// invalidates the earlier ones. ErrPasswordResetTooSoon is returned during the cooldown.
+func (store *SQLStore) RequestPasswordResetTx(ctx context.Context, arg RequestPasswordResetTxParams) (RequestPasswordResetTxResult, error) {
+	var result RequestPasswordResetTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// Lock the user so that concurrent requests cannot both pass the cooldown
+		user, err := q.GetUserForUpdate(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		latest, err := q.GetLatestPasswordReset(ctx, user.Username)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if err == nil && !latest.IsUsed && time.Since(latest.CreatedAt) < arg.Cooldown {
+			return ErrPasswordResetTooSoon
+		}
+
+		// only the most recently sent code can be used
+		if err = q.InvalidatePasswordResets(ctx, user.Username); err != nil {
+			return err
+		}
+
+		result.PasswordReset, err = q.CreatePasswordReset(ctx, CreatePasswordResetParams{
+			Username:   user.Username,
+			Email:      user.Email,
+			SecretCode: arg.SecretCode,
+		})
+		if err != nil {
+			return err
+		}
+
+		message, err := arg.AfterCreate(result.PasswordReset)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateOutboxMessage(ctx, message)
+		return err
+	})
+
+	return result, err
+}
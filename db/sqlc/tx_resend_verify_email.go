@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ResendVerifyEmailTxParams contains the input parameters of the resend verify email transaction
+type ResendVerifyEmailTxParams struct {
+	Username string `json:"username"`
+	// Cooldown is the minimum time between two verification emails of the user
+	Cooldown time.Duration `json:"cooldown"`
+	// NewMessage builds the task written to the outbox for the user
+	NewMessage func(user Users) (CreateOutboxMessageParams, error)
+}
+
+// ResendVerifyEmailTxResult is the result of the resend verify email transaction
+type ResendVerifyEmailTxResult struct {
+	User Users `json:"user"`
+}
+
+// ResendVerifyEmailTx queues another verification email for a user whose email is not verified yet.
+// ErrVerifyEmailTooSoon is returned while the same task was written to the outbox within the cooldown.
+func (store *SQLStore) ResendVerifyEmailTx(ctx context.Context, arg ResendVerifyEmailTxParams) (ResendVerifyEmailTxResult, error) {
+	var result ResendVerifyEmailTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// Lock the user so that concurrent requests cannot both pass the cooldown
+		result.User, err = q.GetUserForUpdate(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		if result.User.IsEmailVerified {
+			return ErrEmailAlreadyVerified
+		}
+
+		message, err := arg.NewMessage(result.User)
+		if err != nil {
+			return err
+		}
+
+		// the outbox keeps every queued email, including those sent at sign up or after an email change
+		latest, err := q.GetLatestOutboxMessage(ctx, GetLatestOutboxMessageParams{
+			TaskType: message.TaskType,
+			Payload:  message.Payload,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if err == nil && time.Since(latest.CreatedAt) < arg.Cooldown {
+			return ErrVerifyEmailTooSoon
+		}
+
+		_, err = q.CreateOutboxMessage(ctx, message)
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// ResetPasswordTxParams contains the input parameters of the reset password transaction
+type ResetPasswordTxParams struct {
+	ResetID        int64  `json:"reset_id"`
+	SecretCode     string `json:"secret_code"`
+	HashedPassword string `json:"hashed_password"`
+}
+
+// ResetPasswordTxResult is the result of the reset password transaction
+type ResetPasswordTxResult struct {
+	User          Users          `json:"user"`
+	PasswordReset PasswordResets `json:"password_reset"`
+}
+
+// ResetPasswordTx consumes a password reset code, sets the new password and blocks every existing session of the user
+func (store *SQLStore) ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error) {
+	var result ResetPasswordTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.PasswordReset, err = q.UsePasswordReset(ctx, UsePasswordResetParams{
+			ID:         arg.ResetID,
+			SecretCode: arg.SecretCode,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.UpdateUser(ctx, UpdateUserParams{
+			Username: result.PasswordReset.Username,
+			HashedPassword: sql.NullString{
+				String: arg.HashedPassword,
+				Valid:  true,
+			},
+			PasswordChangeAt: sql.NullTime{
+				Time:  time.Now(),
+				Valid: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.BlockUserSessions(ctx, result.User.Username)
+	})
+
+	return result, err
+}
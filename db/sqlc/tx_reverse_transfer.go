@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ReverseTransferTxParams contains the input parameters of the reverse transfer transaction
+type ReverseTransferTxParams struct {
+	TransferID int64 `json:"transfer_id"`
+	// Amount is optional; zero refunds everything that has not been reversed yet
+	Amount      int64  `json:"amount"`
+	InitiatedBy string `json:"initiated_by"`
+}
+
+// ReverseTransferTxResult is the result of the reverse transfer transaction
+type ReverseTransferTxResult struct {
+	Reversal Transfers `json:"reversal"`
+	Original Transfers `json:"original"`
+	// FromAccount is the recipient of the original transfer, ToAccount is its sender
+	FromAccount Accounts `json:"from_account"`
+	ToAccount   Accounts `json:"to_account"`
+	FromEntry   Entries  `json:"from_entry"`
+	ToEntry     Entries  `json:"to_entry"`
+	// RemainingAmount is what can still be reversed after this reversal
+	RemainingAmount int64 `json:"remaining_amount"`
+}
+
+// ReverseTransferTx refunds all or part of a customer transfer with a compensating transfer linked to it.
+// The original transfer and its entries are left untouched and its fee is not refunded.
+func (store *SQLStore) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	var result ReverseTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// Lock the original transfer so that concurrent reversals see each other
+		result.Original, err = q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkTransferReversible(ctx, q, result.Original); err != nil {
+			return err
+		}
+
+		reversed, err := q.GetReversedAmount(ctx, sql.NullInt64{Int64: result.Original.ID, Valid: true})
+		if err != nil {
+			return err
+		}
+
+		remaining := result.Original.Amount - reversed
+		if remaining <= 0 {
+			return ErrTransferAlreadyReversed
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = remaining
+		}
+		if amount > remaining {
+			return fmt.Errorf("%w: only %d is left to reverse", ErrReversalTooLarge, remaining)
+		}
+
+		// Money flows back from the recipient to the sender of the original transfer
+		fromAccountID, toAccountID := result.Original.ToAccountID, result.Original.FromAccountID
+
+		fromAccount, toAccount, err := lockAccounts(ctx, q, fromAccountID, toAccountID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkAccountActive(fromAccount); err != nil {
+			return err
+		}
+		if err := checkAccountActive(toAccount); err != nil {
+			return err
+		}
+
+		if fromAccount.Balance-amount < -fromAccount.OverdraftLimit {
+			return ErrInsufficientFunds
+		}
+
+		result.Reversal, err = q.CreateTransfer(ctx, CreateTransferParams{
+			FromAccountID: fromAccountID,
+			ToAccountID:   toAccountID,
+			Amount:        amount,
+			InitiatedBy: sql.NullString{
+				String: arg.InitiatedBy,
+				Valid:  true,
+			},
+			ReversalOf: sql.NullInt64{
+				Int64: result.Original.ID,
+				Valid: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: fromAccountID,
+			Amount:    -amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: toAccountID,
+			Amount:    amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		if fromAccountID < toAccountID {
+			result.FromAccount, result.ToAccount, err = addMoney(ctx, q, fromAccountID, toAccountID, amount)
+		} else {
+			result.ToAccount, result.FromAccount, err = addMoney(ctx, q, toAccountID, fromAccountID, -amount)
+		}
+		if err != nil {
+			return err
+		}
+
+		result.RemainingAmount = remaining - amount
+		return nil
+	})
+
+	return result, err
+}
+
+// checkTransferReversible returns ErrTransferNotReversible unless the transfer was sent by a customer.
+// Cash movements and exchange legs have their own counterparts and reversals cannot be reversed again.
+func checkTransferReversible(ctx context.Context, q *Queries, transfer Transfers) error {
+	if transfer.ReversalOf.Valid {
+		return fmt.Errorf("%w: transfer %d is a reversal", ErrTransferNotReversible, transfer.ID)
+	}
+	if transfer.InitiatedBy.Valid {
+		return fmt.Errorf("%w: transfer %d is a deposit or withdrawal", ErrTransferNotReversible, transfer.ID)
+	}
+
+	_, err := q.GetExchangeByTransfer(ctx, transfer.ID)
+	if err == nil {
+		return fmt.Errorf("%w: transfer %d is part of an exchange", ErrTransferNotReversible, transfer.ID)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	return nil
+}
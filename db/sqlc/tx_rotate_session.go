@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RotateSessionTxParams contains the input parameters of the rotate session transaction
+type RotateSessionTxParams struct {
+	SessionID uuid.UUID `json:"session_id"`
+	// IssuedAt is the issue time of the presented refresh token
+	IssuedAt time.Time `json:"issued_at"`
+	// NewSession builds the replacing session from the current state of the user
+	NewSession func(user Users) (CreateSessionParams, error)
+}
+
+// RotateSessionTxResult is the result of the rotate session transaction
+type RotateSessionTxResult struct {
+	OldSession Sessions `json:"old_session"`
+	NewSession Sessions `json:"new_session"`
+	User       Users    `json:"user"`
+}
+
+// RotateSessionTx replaces a session with a new one in the same family. If the old session
+// was already rotated, its refresh token is being reused and the whole family gets blocked.
+// A refresh token issued before the user's last password change is rejected.
+func (store *SQLStore) RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (RotateSessionTxResult, error) {
+	var result RotateSessionTxResult
+	reused := false
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		// Lock the old session so that concurrent renewals are serialized
+		result.OldSession, err = q.GetSessionForUpdate(ctx, arg.SessionID)
+		if err != nil {
+			return err
+		}
+
+		// A superseded refresh token must never be presented again, so block every session in the family.
+		// The block has to be committed, so the error is only reported after the transaction.
+		if result.OldSession.SupersededBy.Valid {
+			reused = true
+			return q.BlockSessionFamily(ctx, result.OldSession.FamilyID)
+		}
+
+		if result.OldSession.IsBlocked {
+			return ErrSessionBlocked
+		}
+
+		// Reload the user so that password and role changes apply to the new tokens
+		result.User, err = q.GetUser(ctx, result.OldSession.Username)
+		if err != nil {
+			return err
+		}
+
+		if arg.IssuedAt.Before(result.User.PasswordChangeAt) {
+			return ErrPasswordChanged
+		}
+
+		newSession, err := arg.NewSession(result.User)
+		if err != nil {
+			return err
+		}
+
+		newSession.FamilyID = result.OldSession.FamilyID
+		result.NewSession, err = q.CreateSession(ctx, newSession)
+		if err != nil {
+			return err
+		}
+
+		result.OldSession, err = q.SupersedeSession(ctx, SupersedeSessionParams{
+			ID: result.OldSession.ID,
+			SupersededBy: uuid.NullUUID{
+				UUID:  result.NewSession.ID,
+				Valid: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err == nil && reused {
+		err = ErrRefreshTokenReused
+	}
+
+	return result, err
+}
@@ -2,6 +2,15 @@ package db
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/guncv/Simple-Bank/util"
 )
 
 // TransferTxParams contains the input parameters of the transfer transaction
@@ -9,6 +18,10 @@ type TransferTxParams struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
 	Amount        int64 `json:"amount"`
+	// IdempotencyKey is optional; when set, retries with the same key replay the original result
+	IdempotencyKey string `json:"idempotency_key"`
+	// QuoteID is optional; when set, the quote is consumed and its fee is charged
+	QuoteID uuid.NullUUID `json:"quote_id"`
 }
 
 // TransferTxResult is the result of the transfer transaction
@@ -18,6 +31,10 @@ type TransferTxResult struct {
 	ToAccount   Accounts  `json:"to_account"`
 	FromEntry   Entries   `json:"from_entry"`
 	ToEntry     Entries   `json:"to_entry"`
+	// FeeEntry credits the revenue account and is empty when no fee was charged
+	FeeEntry Entries `json:"fee_entry"`
+	// Replayed is true when the result was returned from a previously stored idempotency key
+	Replayed bool `json:"-"`
 }
 
 // TransferTx performs a money transfer from one account to another
@@ -26,49 +43,238 @@ func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (Tr
 
 	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
+		result, err = runTransfer(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
 
-		// Create transfer record
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccountID,
-			ToAccountID:   arg.ToAccountID,
-			Amount:        arg.Amount,
-		})
+// runTransfer holds the logic of TransferTx so that it can also be dry-run by QuoteTransferTx
+func runTransfer(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	// Lock both accounts in a consistent order to avoid deadlocks
+	fromAccount, toAccount, err := lockAccounts(ctx, q, arg.FromAccountID, arg.ToAccountID)
+	if err != nil {
+		return result, err
+	}
+
+	// Claim the idempotency key, or replay the stored result if it was already used
+	if arg.IdempotencyKey != "" {
+		claimed, err := claimIdempotencyKey(ctx, q, fromAccount.Owner, arg.IdempotencyKey, arg.requestHash(), &result)
 		if err != nil {
-			return err
+			return result, err
+		}
+		if !claimed {
+			result.Replayed = true
+			return result, nil
 		}
+	}
+
+	// Frozen and closed accounts can neither be debited nor credited
+	if err := checkAccountActive(fromAccount); err != nil {
+		return result, err
+	}
+	if err := checkAccountActive(toAccount); err != nil {
+		return result, err
+	}
+	if err := checkCustomerAccount(ctx, q, toAccount); err != nil {
+		return result, err
+	}
 
-		// Create entries for from and to accounts
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
-		})
+	// Lock the owner so that concurrent transfers are counted against the same limits
+	owner, err := q.GetUserForUpdate(ctx, fromAccount.Owner)
+	if err != nil {
+		return result, err
+	}
+
+	if err := checkTransferLimit(ctx, q, owner, fromAccount.Currency, arg.Amount); err != nil {
+		return result, err
+	}
+
+	// A quote guarantees its fee, otherwise the fee depends on the currency and on the role of the account owner
+	var fee int64
+	if arg.QuoteID.Valid {
+		quote, err := useQuote(ctx, q, arg.QuoteID.UUID, arg.FromAccountID, arg.ToAccountID, arg.Amount)
 		if err != nil {
-			return err
+			return result, err
+		}
+		if quote.ExchangeRateID.Valid {
+			return result, fmt.Errorf("%w: quote is for a cross-currency transfer", ErrQuoteMismatch)
 		}
+		fee = quote.Fee
+	} else {
+		fee, err = transferFee(ctx, q, fromAccount.Currency, owner.Role, arg.Amount)
+		if err != nil {
+			return result, err
+		}
+	}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
+	// Reject the transfer if it would go below the allowed floor
+	if fromAccount.Balance-arg.Amount-fee < -fromAccount.OverdraftLimit {
+		return result, ErrInsufficientFunds
+	}
+
+	// Create transfer record
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+		Fee:           fee,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// Create entries for from and to accounts, the fee is debited together with the amount
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.FromAccountID,
+		Amount:    -arg.Amount - fee,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: arg.ToAccountID,
+		Amount:    arg.Amount,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// Update account balances
+	if arg.FromAccountID < arg.ToAccountID {
+		result.FromAccount, result.ToAccount, err = addMoney(ctx, q, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	} else {
+		result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, arg.FromAccountID, -arg.Amount)
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	// Credit the fee to the bank's revenue account
+	if fee > 0 {
+		result.FeeEntry, result.FromAccount, err = chargeFee(ctx, q, fromAccount, fee)
 		if err != nil {
-			return err
+			return result, err
 		}
+	}
 
-		// Update account balances
-		if arg.FromAccountID < arg.ToAccountID {
-			result.FromAccount, result.ToAccount, err = addMoney(ctx, q, arg.FromAccountID, arg.ToAccountID, arg.Amount)
-		} else {
-			result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, arg.FromAccountID, -arg.Amount)
+	// Store the result so that retries with the same key return it
+	if arg.IdempotencyKey != "" {
+		if err := storeIdempotencyResponse(ctx, q, fromAccount.Owner, arg.IdempotencyKey, result); err != nil {
+			return result, err
 		}
+	}
+
+	return result, nil
+}
+
+// requestHash fingerprints the transfer so that a reused idempotency key with a different body can be detected.
+// The quote is part of the body since it decides the fee charged.
+func (arg TransferTxParams) requestHash() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%d:%d:%s", arg.FromAccountID, arg.ToAccountID, arg.Amount, quoteKey(arg.QuoteID))))
+	return hex.EncodeToString(sum[:])
+}
+
+// quoteKey is the quote ID as used in a request hash, empty when no quote was given
+func quoteKey(quoteID uuid.NullUUID) string {
+	if !quoteID.Valid {
+		return ""
+	}
+	return quoteID.UUID.String()
+}
+
+// claimIdempotencyKey records the key for the given user. It returns false and fills result with the
+// stored response when the key was already used for the same request.
+func claimIdempotencyKey(ctx context.Context, q *Queries, username string, key string, requestHash string, result any) (bool, error) {
+	_, err := q.CreateIdempotencyKey(ctx, CreateIdempotencyKeyParams{
+		Username:    username,
+		Key:         key,
+		RequestHash: requestHash,
+	})
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+
+	// ON CONFLICT DO NOTHING returns no row when the key already exists
+	idempotencyKey, err := q.GetIdempotencyKey(ctx, GetIdempotencyKeyParams{
+		Username: username,
+		Key:      key,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if idempotencyKey.RequestHash != requestHash {
+		return false, ErrIdempotencyKeyConflict
+	}
+
+	if err := json.Unmarshal(idempotencyKey.Response, result); err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
+
+// storeIdempotencyResponse saves the result of the request that claimed the key
+func storeIdempotencyResponse(ctx context.Context, q *Queries, username string, key string, result any) error {
+	response, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
 
+	return q.UpdateIdempotencyKeyResponse(ctx, UpdateIdempotencyKeyResponseParams{
+		Username: username,
+		Key:      key,
+		Response: response,
+	})
+}
+
+// lockAccounts locks both accounts with FOR NO KEY UPDATE in ascending ID order
+func lockAccounts(ctx context.Context, q *Queries, fromAccountID int64, toAccountID int64) (fromAccount Accounts, toAccount Accounts, err error) {
+	if fromAccountID < toAccountID {
+		fromAccount, err = q.GetAccountForUpdate(ctx, fromAccountID)
 		if err != nil {
-			return err
+			return
 		}
+		toAccount, err = q.GetAccountForUpdate(ctx, toAccountID)
+		return
+	}
 
-		return nil
-	})
+	toAccount, err = q.GetAccountForUpdate(ctx, toAccountID)
+	if err != nil {
+		return
+	}
+	fromAccount, err = q.GetAccountForUpdate(ctx, fromAccountID)
+	return
+}
 
-	return result, err
+// checkAccountActive returns ErrAccountNotActive unless money can move in or out of the account
+func checkAccountActive(account Accounts) error {
+	if account.Status != AccountStatusActive {
+		return fmt.Errorf("%w: account %d is %s", ErrAccountNotActive, account.ID, account.Status)
+	}
+	return nil
+}
+
+// checkCustomerAccount rejects accounts owned by a system user. Their balances only move as the
+// counterpart of deposits, withdrawals, exchanges and fees, never as the target of a customer transfer.
+func checkCustomerAccount(ctx context.Context, q *Queries, account Accounts) error {
+	owner, err := q.GetUser(ctx, account.Owner)
+	if err != nil {
+		return err
+	}
+	if util.Role(owner.Role) == util.SystemRole {
+		return fmt.Errorf("%w: account %d", ErrSystemAccount, account.ID)
+	}
+	return nil
 }
 
 // addMoney adjusts account balances
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// UpdateUserTxParams contains the input parameters of the update user transaction
+type UpdateUserTxParams struct {
+	UpdateUserParams
+	// AfterEmailChange builds the task written to the outbox when the email changes
+	AfterEmailChange func(user Users) (CreateOutboxMessageParams, error)
+}
+
+// UpdateUserTxResult is the result of the update user transaction
+type UpdateUserTxResult struct {
+	User         Users `json:"user"`
+	EmailChanged bool  `json:"email_changed"`
+}
+
+// UpdateUserTx updates a user. Changing the password blocks every session of the user. Changing the email
+// marks the user unverified, invalidates pending verification codes and queues the task built by AfterEmailChange
+func (store *SQLStore) UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (UpdateUserTxResult, error) {
+	var result UpdateUserTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		params := arg.UpdateUserParams
+
+		if params.Email.Valid {
+			user, err := q.GetUser(ctx, params.Username)
+			if err != nil {
+				return err
+			}
+
+			if user.Email != params.Email.String {
+				result.EmailChanged = true
+				params.IsEmailVerified = sql.NullBool{
+					Bool:  false,
+					Valid: true,
+				}
+			}
+		}
+
+		var err error
+		result.User, err = q.UpdateUser(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		if params.HashedPassword.Valid {
+			if err = q.BlockUserSessions(ctx, result.User.Username); err != nil {
+				return err
+			}
+		}
+
+		if !result.EmailChanged {
+			return nil
+		}
+
+		err = q.InvalidateVerifyEmails(ctx, result.User.Username)
+		if err != nil {
+			return err
+		}
+
+		if arg.AfterEmailChange == nil {
+			return nil
+		}
+
+		message, err := arg.AfterEmailChange(result.User)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateOutboxMessage(ctx, message)
+		return err
+	})
+
+	return result, err
+}
@@ -152,3 +152,171 @@ func TestUpdateUserAllFields(t *testing.T) {
 	require.WithinDuration(t, oldUser.PasswordChangeAt, updatedUser.PasswordChangeAt, time.Second)
 	require.WithinDuration(t, oldUser.CreatedAt, updatedUser.CreatedAt, time.Second)
 }
+
+func TestUpdateUserTxEmailChange(t *testing.T) {
+	store := NewStore(testDB)
+	oldUser := createRandomUser(t)
+
+	_, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username: oldUser.Username,
+		IsEmailVerified: sql.NullBool{
+			Bool:  true,
+			Valid: true,
+		},
+	})
+	require.NoError(t, err)
+
+	verifyEmail, err := testQueries.CreateVerifyEmail(context.Background(), CreateVerifyEmailParams{
+		Username:   oldUser.Username,
+		Email:      oldUser.Email,
+		SecretCode: util.RandomString(32),
+	})
+	require.NoError(t, err)
+
+	var notified Users
+	newEmail := util.RandomEmail()
+	result, err := store.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username: oldUser.Username,
+			Email: sql.NullString{
+				String: newEmail,
+				Valid:  true,
+			},
+		},
+		AfterEmailChange: func(user Users) (CreateOutboxMessageParams, error) {
+			notified = user
+			return newOutboxMessageParams(user.Username), nil
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, result.EmailChanged)
+	require.Equal(t, newEmail, result.User.Email)
+	require.False(t, result.User.IsEmailVerified)
+	require.Equal(t, result.User, notified)
+	require.Equal(t, 1, countOutboxMessages(t, oldUser.Username))
+
+	// codes sent to the old address no longer work
+	_, err = testQueries.UpdateVerifyEmail(context.Background(), UpdateVerifyEmailParams{
+		ID:         verifyEmail.ID,
+		SecretCode: verifyEmail.SecretCode,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUpdateUserTxSameEmail(t *testing.T) {
+	store := NewStore(testDB)
+	oldUser := createRandomUser(t)
+
+	result, err := store.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username: oldUser.Username,
+			Email: sql.NullString{
+				String: oldUser.Email,
+				Valid:  true,
+			},
+		},
+		AfterEmailChange: func(user Users) (CreateOutboxMessageParams, error) {
+			t.Fatal("AfterEmailChange must not run when the email is unchanged")
+			return CreateOutboxMessageParams{}, nil
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, result.EmailChanged)
+	require.Equal(t, oldUser.Email, result.User.Email)
+	require.Zero(t, countOutboxMessages(t, oldUser.Username))
+}
+
+func TestDeleteStaleVerifyEmails(t *testing.T) {
+	user := createRandomUser(t)
+
+	verifyEmail, err := testQueries.CreateVerifyEmail(context.Background(), CreateVerifyEmailParams{
+		Username:   user.Username,
+		Email:      user.Email,
+		SecretCode: util.RandomString(32),
+	})
+	require.NoError(t, err)
+
+	_, err = testDB.ExecContext(context.Background(),
+		`UPDATE verify_emails SET expired_at = now() - interval '2 days' WHERE id = $1`, verifyEmail.ID)
+	require.NoError(t, err)
+
+	deleted, err := testQueries.DeleteStaleVerifyEmails(context.Background(), time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, deleted, int64(1))
+
+	_, err = testQueries.GetLatestVerifyEmail(context.Background(), user.Username)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUpdateUserTxPasswordBlocksSessions(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	createRandomSession(t, user.Username)
+	createRandomSession(t, user.Username)
+
+	hashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+
+	_, err = store.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username: user.Username,
+			HashedPassword: sql.NullString{
+				String: hashedPassword,
+				Valid:  true,
+			},
+			PasswordChangeAt: sql.NullTime{
+				Time:  time.Now(),
+				Valid: true,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	sessions, err := testQueries.ListActiveSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+}
+
+func resendVerifyEmail(store Store, user Users, cooldown time.Duration) (ResendVerifyEmailTxResult, error) {
+	return store.ResendVerifyEmailTx(context.Background(), ResendVerifyEmailTxParams{
+		Username: user.Username,
+		Cooldown: cooldown,
+		NewMessage: func(user Users) (CreateOutboxMessageParams, error) {
+			return newOutboxMessageParams(user.Username), nil
+		},
+	})
+}
+
+func TestResendVerifyEmailTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	result, err := resendVerifyEmail(store, user, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, user.Username, result.User.Username)
+	require.Equal(t, 1, countOutboxMessages(t, user.Username))
+
+	// the message still waiting in the outbox blocks new requests during the cooldown
+	_, err = resendVerifyEmail(store, user, time.Minute)
+	require.ErrorIs(t, err, ErrVerifyEmailTooSoon)
+	require.Equal(t, 1, countOutboxMessages(t, user.Username))
+
+	_, err = resendVerifyEmail(store, user, 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, countOutboxMessages(t, user.Username))
+}
+
+func TestResendVerifyEmailTxAlreadyVerified(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	_, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username:        user.Username,
+		IsEmailVerified: sql.NullBool{Bool: true, Valid: true},
+	})
+	require.NoError(t, err)
+
+	_, err = resendVerifyEmail(store, user, 0)
+	require.ErrorIs(t, err, ErrEmailAlreadyVerified)
+	require.Zero(t, countOutboxMessages(t, user.Username))
+}
@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
 const createVerifyEmail = `-- name: CreateVerifyEmail :one
@@ -40,6 +41,55 @@ func (q *Queries) CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailPa
 	return i, err
 }
 
+const deleteStaleVerifyEmails = `-- name: DeleteStaleVerifyEmails :execrows
+DELETE FROM verify_emails
+WHERE expired_at < $1
+`
+
+func (q *Queries) DeleteStaleVerifyEmails(ctx context.Context, expiredBefore time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteStaleVerifyEmails, expiredBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getLatestVerifyEmail = `-- name: GetLatestVerifyEmail :one
+SELECT id, username, email, secret_code, is_used, expired_at, created_at FROM verify_emails
+WHERE username = $1
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestVerifyEmail(ctx context.Context, username string) (VerifyEmails, error) {
+	row := q.db.QueryRowContext(ctx, getLatestVerifyEmail, username)
+	var i VerifyEmails
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.SecretCode,
+		&i.IsUsed,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const invalidateVerifyEmails = `-- name: InvalidateVerifyEmails :exec
+UPDATE verify_emails
+SET expired_at = now()
+WHERE
+    username = $1 AND
+    is_used = FALSE AND
+    expired_at > now()
+`
+
+func (q *Queries) InvalidateVerifyEmails(ctx context.Context, username string) error {
+	_, err := q.db.ExecContext(ctx, invalidateVerifyEmails, username)
+	return err
+}
+
 const updateVerifyEmail = `-- name: UpdateVerifyEmail :one
 UPDATE verify_emails
 SET is_used = TRUE
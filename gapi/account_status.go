@@ -0,0 +1,61 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// changeAccountStatus moves an account from one status to another, failing if it is not currently in the from status
+func (server *Server) changeAccountStatus(ctx context.Context, accountID int64, from db.AccountStatus, to db.AccountStatus) (db.Accounts, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return account, status.Errorf(codes.NotFound, "account not found")
+		}
+		return account, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	if err := server.checkCustomerAccount(ctx, account); err != nil {
+		return account, err
+	}
+
+	if account.Status != from {
+		return account, status.Errorf(codes.FailedPrecondition, "account [%d] is %s, expected %s", account.ID, account.Status, from)
+	}
+
+	account, err = server.store.UpdateAccountStatus(ctx, db.UpdateAccountStatusParams{
+		ID:         accountID,
+		FromStatus: from,
+		ToStatus:   to,
+	})
+	if err != nil {
+		// the status changed between the read and the update
+		if errors.Is(err, sql.ErrNoRows) {
+			return account, status.Errorf(codes.FailedPrecondition, "account [%d] is no longer %s", accountID, from)
+		}
+		return account, status.Errorf(codes.Internal, "failed to update account status: %s", err)
+	}
+
+	return account, nil
+}
+
+// checkCustomerAccount rejects accounts owned by a system user. Their balances are only moved as the
+// counterpart of customer operations, so bankers must not freeze, close or deposit into them directly.
+func (server *Server) checkCustomerAccount(ctx context.Context, account db.Accounts) error {
+	owner, err := server.store.GetUser(ctx, account.Owner)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get account owner: %s", err)
+	}
+
+	if util.Role(owner.Role) == util.SystemRole {
+		return permissionDeniedError(fmt.Errorf("account [%d] is a system account", account.ID))
+	}
+	return nil
+}
@@ -2,8 +2,11 @@ package gapi
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/guncv/Simple-Bank/token"
 	"github.com/guncv/Simple-Bank/util"
@@ -46,6 +49,10 @@ func (server *Server) authorizeUser(ctx context.Context, accessibleRoles []util.
 		return nil, status.Errorf(codes.Unauthenticated, "access token is invalid: %v", err)
 	}
 
+	if err := server.checkSession(ctx, payload); err != nil {
+		return nil, err
+	}
+
 	if !hasPermission(payload.Role, accessibleRoles) {
 		return nil, status.Errorf(codes.PermissionDenied, "you are not allowed to access this resource")
 	}
@@ -53,6 +60,36 @@ func (server *Server) authorizeUser(ctx context.Context, accessibleRoles []util.
 	return payload, nil
 }
 
+// checkSession rejects tokens whose session has been blocked or expired, or that were issued before the user's last password change
+func (server *Server) checkSession(ctx context.Context, payload *token.Payload) error {
+	info, ok := server.sessionCache.get(payload.SessionID)
+	if !ok {
+		var err error
+		info, err = server.store.GetSessionAuthInfo(ctx, payload.SessionID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return status.Errorf(codes.Unauthenticated, "session not found")
+			}
+			return status.Errorf(codes.Internal, "failed to get session: %v", err)
+		}
+		server.sessionCache.set(payload.SessionID, info)
+	}
+
+	if info.IsBlocked {
+		return status.Errorf(codes.Unauthenticated, "session is blocked")
+	}
+
+	if time.Now().After(info.ExpiresAt) {
+		return status.Errorf(codes.Unauthenticated, "session expired")
+	}
+
+	if payload.IssuedAt.Before(info.PasswordChangeAt) {
+		return status.Errorf(codes.Unauthenticated, "access token was issued before the last password change")
+	}
+
+	return nil
+}
+
 func hasPermission(role util.Role, accessibleRoles []util.Role) bool {
 	for _, accessibleRole := range accessibleRoles {
 		if role == accessibleRole {
@@ -0,0 +1,178 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/token"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorizeUserSession(t *testing.T) {
+	user, _ := randomUser(t)
+	sessionID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, payload *token.Payload, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetSessionAuthInfo(gomock.Any(), gomock.Eq(sessionID)).Times(1).
+					Return(db.GetSessionAuthInfoRow{Username: user.Username, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.NoError(t, err)
+				require.Equal(t, sessionID, payload.SessionID)
+			},
+		},
+		{
+			name: "BlockedSession",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetSessionAuthInfo(gomock.Any(), gomock.Eq(sessionID)).Times(1).
+					Return(db.GetSessionAuthInfoRow{Username: user.Username, IsBlocked: true, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "ExpiredSession",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetSessionAuthInfo(gomock.Any(), gomock.Eq(sessionID)).Times(1).
+					Return(db.GetSessionAuthInfoRow{Username: user.Username, ExpiresAt: time.Now().Add(-time.Minute)}, nil)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "PasswordChanged",
+			buildStubs: func(store *mockdb.MockStore) {
+				info := db.GetSessionAuthInfoRow{
+					Username:         user.Username,
+					ExpiresAt:        time.Now().Add(time.Hour),
+					PasswordChangeAt: time.Now().Add(time.Second),
+				}
+				store.EXPECT().GetSessionAuthInfo(gomock.Any(), gomock.Eq(sessionID)).Times(1).Return(info, nil)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "SessionNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetSessionAuthInfo(gomock.Any(), gomock.Eq(sessionID)).Times(1).
+					Return(db.GetSessionAuthInfoRow{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetSessionAuthInfo(gomock.Any(), gomock.Eq(sessionID)).Times(1).
+					Return(db.GetSessionAuthInfoRow{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, payload *token.Payload, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			accessToken, _, err := server.tokenMaker.CreateToken(user.Username, util.DepositorRole, sessionID, time.Minute)
+			require.NoError(t, err)
+
+			md := metadata.MD{
+				authorizationHeaderKey: []string{fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken)},
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+
+			payload, err := server.authorizeUser(ctx, []util.Role{util.DepositorRole})
+			tc.checkResponse(t, payload, err)
+		})
+	}
+}
+
+func TestAuthorizeUserSessionCache(t *testing.T) {
+	user, _ := randomUser(t)
+	sessionID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	info := db.GetSessionAuthInfoRow{Username: user.Username, FamilyID: sessionID, ExpiresAt: time.Now().Add(time.Hour)}
+	store.EXPECT().GetSessionAuthInfo(gomock.Any(), gomock.Eq(sessionID)).Times(2).Return(info, nil)
+
+	server := newTestServer(t, store, nil)
+	accessToken, _, err := server.tokenMaker.CreateToken(user.Username, util.DepositorRole, sessionID, time.Minute)
+	require.NoError(t, err)
+
+	md := metadata.MD{
+		authorizationHeaderKey: []string{fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken)},
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	// the second call is served from the cache
+	for i := 0; i < 2; i++ {
+		_, err = server.authorizeUser(ctx, []util.Role{util.DepositorRole})
+		require.NoError(t, err)
+	}
+
+	// invalidating the family forces a new lookup
+	server.sessionCache.invalidateFamily(sessionID)
+	_, err = server.authorizeUser(ctx, []util.Role{util.DepositorRole})
+	require.NoError(t, err)
+}
+
+func TestSessionLookupFailureIsInternal(t *testing.T) {
+	user, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetSessionAuthInfo(gomock.Any(), gomock.Any()).Times(1).Return(db.GetSessionAuthInfoRow{}, sql.ErrConnDone)
+	store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store, nil)
+	ctx := newContextWithBearerToken(t, server.tokenMaker, user.Username, util.DepositorRole, time.Minute)
+
+	// the RPC must not turn a database failure into an authentication failure
+	_, err := server.ListSessions(ctx, &pb.ListSessionsRequest{})
+	require.Error(t, err)
+	require.Equal(t, codes.Internal, status.Code(err))
+}
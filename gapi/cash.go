@@ -0,0 +1,42 @@
+package gapi
+
+import (
+	"database/sql"
+	"errors"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// cashTxError maps errors of the deposit and withdraw transactions to gRPC status errors
+func cashTxError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Errorf(codes.NotFound, "account or cash account not found")
+	}
+	if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrAccountNotActive) {
+		return status.Errorf(codes.FailedPrecondition, "%s", err)
+	}
+	return status.Errorf(codes.Internal, "failed to move cash: %s", err)
+}
+
+func validateCashRequest(accountID int64, amount int64, currency string, currencies util.Currencies) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := util.ValidateAccountId(accountID); err != nil {
+		violations = append(violations, fieldViolations("account_id", err))
+	}
+	if err := util.ValidateAmount(amount); err != nil {
+		violations = append(violations, fieldViolations("amount", err))
+	}
+	if err := currencies.Validate(currency); err != nil {
+		violations = append(violations, fieldViolations("currency", err))
+	}
+	return violations
+}
+
+func convertCashTxResult(currencies util.Currencies, result db.CashTxResult) (*pb.Transfer, *pb.Account, *pb.Entry) {
+	currency := result.Account.Currency
+	return convertTransfer(currencies, result.Transfer, currency), convertAccount(currencies, result.Account), convertEntry(currencies, result.Entry, currency)
+}
@@ -1,8 +1,12 @@
 package gapi
 
 import (
+	"encoding/base64"
+
 	db "github.com/guncv/Simple-Bank/db/sqlc"
 	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/token"
+	"github.com/guncv/Simple-Bank/util"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -13,5 +17,139 @@ func convertUser(user db.Users) *pb.User {
 		Email:             user.Email,
 		PasswordChangedAt: timestamppb.New(user.PasswordChangeAt),
 		CreatedAt:         timestamppb.New(user.CreatedAt),
+		IsEmailVerified:   user.IsEmailVerified,
+	}
+}
+
+func convertAccount(currencies util.Currencies, account db.Accounts) *pb.Account {
+	return &pb.Account{
+		Id:               account.ID,
+		Owner:            account.Owner,
+		Balance:          account.Balance,
+		Currency:         account.Currency,
+		CreatedAt:        timestamppb.New(account.CreatedAt),
+		OverdraftLimit:   account.OverdraftLimit,
+		Status:           string(account.Status),
+		FormattedBalance: currencies.FormatAmount(account.Balance, account.Currency),
+	}
+}
+
+func convertTransfer(currencies util.Currencies, transfer db.Transfers, currency string) *pb.Transfer {
+	return &pb.Transfer{
+		Id:              transfer.ID,
+		FromAccountId:   transfer.FromAccountID,
+		ToAccountId:     transfer.ToAccountID,
+		Amount:          transfer.Amount,
+		CreatedAt:       timestamppb.New(transfer.CreatedAt),
+		InitiatedBy:     transfer.InitiatedBy.String,
+		Currency:        currency,
+		FormattedAmount: currencies.FormatAmount(transfer.Amount, currency),
+		Fee:             transfer.Fee,
+		FormattedFee:    currencies.FormatAmount(transfer.Fee, currency),
+		ReversalOf:      transfer.ReversalOf.Int64,
+	}
+}
+
+// convertAccountTransfer describes a transfer from the side of the listed account. Exchange legs report
+// the customer account on the other side together with the amount it received or sent in its own currency.
+func convertAccountTransfer(currencies util.Currencies, transfer db.ListAccountTransfersRow, currency string) *pb.Transfer {
+	formattedCounterpartyAmount := currencies.FormatAmount(transfer.CounterpartyAmount, transfer.CounterpartyCurrency)
+	resp := &pb.Transfer{
+		Id:                          transfer.ID,
+		FromAccountId:               transfer.FromAccountID,
+		ToAccountId:                 transfer.ToAccountID,
+		Amount:                      transfer.Amount,
+		CreatedAt:                   timestamppb.New(transfer.CreatedAt),
+		InitiatedBy:                 transfer.InitiatedBy.String,
+		Currency:                    currency,
+		FormattedAmount:             currencies.FormatAmount(transfer.Amount, currency),
+		Fee:                         transfer.Fee,
+		FormattedFee:                currencies.FormatAmount(transfer.Fee, currency),
+		ReversalOf:                  transfer.ReversalOf.Int64,
+		CounterpartyAccountId:       &transfer.CounterpartyAccountID,
+		CounterpartyCurrency:        &transfer.CounterpartyCurrency,
+		CounterpartyAmount:          &transfer.CounterpartyAmount,
+		FormattedCounterpartyAmount: &formattedCounterpartyAmount,
+	}
+	if transfer.ExchangeRate.Valid {
+		resp.ExchangeRate = &transfer.ExchangeRate.String
+	}
+	return resp
+}
+
+func convertEntry(currencies util.Currencies, entry db.Entries, currency string) *pb.Entry {
+	return &pb.Entry{
+		Id:              entry.ID,
+		AccountId:       entry.AccountID,
+		Amount:          entry.Amount,
+		CreatedAt:       timestamppb.New(entry.CreatedAt),
+		FormattedAmount: currencies.FormatAmount(entry.Amount, currency),
+	}
+}
+
+func convertAccountEntry(currencies util.Currencies, entry db.ListAccountEntriesRow, currency string) *pb.Entry {
+	formattedBalanceAfter := currencies.FormatAmount(entry.BalanceAfter, currency)
+	return &pb.Entry{
+		Id:                    entry.ID,
+		AccountId:             entry.AccountID,
+		Amount:                entry.Amount,
+		CreatedAt:             timestamppb.New(entry.CreatedAt),
+		FormattedAmount:       currencies.FormatAmount(entry.Amount, currency),
+		BalanceAfter:          &entry.BalanceAfter,
+		FormattedBalanceAfter: &formattedBalanceAfter,
+	}
+}
+
+func convertSession(session db.Sessions) *pb.Session {
+	return &pb.Session{
+		Id:        session.ID.String(),
+		Username:  session.Username,
+		UserAgent: session.UserAgent,
+		ClientIp:  session.ClientIp,
+		CreatedAt: timestamppb.New(session.CreatedAt),
+		ExpiresAt: timestamppb.New(session.ExpiresAt),
+	}
+}
+
+func convertPublicKey(key token.PublicKey) *pb.PublicKey {
+	publicKey := &pb.PublicKey{
+		Kid:       key.ID,
+		Kty:       "OKP",
+		Crv:       "Ed25519",
+		Alg:       key.Algorithm,
+		Use:       "sig",
+		X:         base64.RawURLEncoding.EncodeToString(key.Key),
+		NotBefore: timestamppb.New(key.NotBefore),
+	}
+	if !key.NotAfter.IsZero() {
+		publicKey.NotAfter = timestamppb.New(key.NotAfter)
+	}
+	return publicKey
+}
+
+func convertExchangeRate(rate db.ExchangeRates) *pb.ExchangeRate {
+	return &pb.ExchangeRate{
+		Id:           rate.ID,
+		FromCurrency: rate.FromCurrency,
+		ToCurrency:   rate.ToCurrency,
+		Rate:         rate.Rate,
+		EffectiveAt:  timestamppb.New(rate.EffectiveAt),
+		CreatedBy:    rate.CreatedBy,
+	}
+}
+
+func convertFeeSchedule(schedule db.FeeSchedules) *pb.FeeSchedule {
+	feeSchedule := &pb.FeeSchedule{
+		Currency:      schedule.Currency,
+		Role:          schedule.Role,
+		FlatFee:       schedule.FlatFee,
+		PercentageBps: schedule.PercentageBps,
+		MinFee:        schedule.MinFee,
+		UpdatedBy:     schedule.UpdatedBy,
+		UpdatedAt:     timestamppb.New(schedule.UpdatedAt),
+	}
+	if schedule.MaxFee.Valid {
+		feeSchedule.MaxFee = &schedule.MaxFee.Int64
 	}
+	return feeSchedule
 }
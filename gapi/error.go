@@ -24,7 +24,12 @@ func invalidArgumentError(violations []*errdetails.BadRequest_FieldViolation) er
 	return details.Err()
 }
 
+// unauthenticatedError keeps Internal statuses as they are, so a failed session lookup is not
+// reported to the client as bad credentials
 func unauthenticatedError(err error) error {
+	if st, ok := status.FromError(err); ok && st.Code() == codes.Internal {
+		return err
+	}
 	return status.Errorf(codes.Unauthenticated, "unauthenticated: %v", err)
 }
 
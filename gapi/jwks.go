@@ -0,0 +1,58 @@
+package gapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/guncv/Simple-Bank/pb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// JWKSPath is the well-known path where the token verification keys are published
+const JWKSPath = "/.well-known/jwks.json"
+
+// jwksCacheMaxAge tells verifiers how long they may cache the key set, in seconds
+const jwksCacheMaxAge = "300"
+
+// JWKSHandler serves the token verification keys as a JSON Web Key Set
+func (server *Server) JWKSHandler() http.Handler {
+	marshaler := protojson.MarshalOptions{UseProtoNames: true}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		resp, err := server.ListPublicKeys(r.Context(), &pb.ListPublicKeysRequest{})
+		if err != nil {
+			http.Error(w, "cannot list public keys", http.StatusInternalServerError)
+			return
+		}
+
+		jwks := struct {
+			Keys []json.RawMessage `json:"keys"`
+		}{
+			Keys: make([]json.RawMessage, 0, len(resp.GetKeys())),
+		}
+		for _, key := range resp.GetKeys() {
+			data, err := marshaler.Marshal(key)
+			if err != nil {
+				http.Error(w, "cannot encode public key", http.StatusInternalServerError)
+				return
+			}
+			jwks.Keys = append(jwks.Keys, data)
+		}
+
+		data, err := json.Marshal(jwks)
+		if err != nil {
+			http.Error(w, "cannot encode key set", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age="+jwksCacheMaxAge)
+		w.Write(data)
+	})
+}
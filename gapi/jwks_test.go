@@ -0,0 +1,64 @@
+package gapi
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/guncv/Simple-Bank/token"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKSHandler(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	notBefore := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	tokenMaker, err := token.NewPasetoV4Maker([]token.Ed25519Key{
+		{ID: "key-1", PrivateKey: privateKey, NotBefore: notBefore},
+		// retired keys are no longer published
+		{ID: "key-0", PublicKey: publicKey, NotAfter: time.Now().Add(-time.Minute)},
+	}, "key-1")
+	require.NoError(t, err)
+
+	server := newTestServer(t, nil, nil)
+	server.tokenMaker = tokenMaker
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, JWKSPath, nil)
+	server.JWKSHandler().ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	var jwks struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &jwks))
+	require.Len(t, jwks.Keys, 1)
+
+	key := jwks.Keys[0]
+	require.Equal(t, "key-1", key["kid"])
+	require.Equal(t, "OKP", key["kty"])
+	require.Equal(t, "Ed25519", key["crv"])
+	require.Equal(t, "v4.public", key["alg"])
+	require.Equal(t, "sig", key["use"])
+	require.Equal(t, base64.RawURLEncoding.EncodeToString(publicKey), key["x"])
+	require.Equal(t, notBefore.Format(time.RFC3339), key["not_before"])
+	require.NotContains(t, key, "not_after")
+}
+
+func TestJWKSHandlerSymmetricMaker(t *testing.T) {
+	server := newTestServer(t, nil, nil)
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, JWKSPath, nil)
+	server.JWKSHandler().ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"keys":[]}`, recorder.Body.String())
+}
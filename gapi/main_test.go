@@ -6,6 +6,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
 	db "github.com/guncv/Simple-Bank/db/sqlc"
 	"github.com/guncv/Simple-Bank/token"
 	"github.com/guncv/Simple-Bank/util"
@@ -28,7 +31,7 @@ func newTestServer(t *testing.T, store db.Store, taskDistributor worker.TaskDist
 
 func newContextWithBearerToken(t *testing.T, tokenMaker token.Maker, username string, role util.Role, duration time.Duration) context.Context {
 	ctx := context.Background()
-	newToken, _, err := tokenMaker.CreateToken(username, role, duration)
+	newToken, _, err := tokenMaker.CreateToken(username, role, uuid.New(), duration)
 	require.NoError(t, err)
 
 	bearerToken := fmt.Sprintf("%s %s", authorizationTypeBearer, newToken)
@@ -37,3 +40,31 @@ func newContextWithBearerToken(t *testing.T, tokenMaker token.Maker, username st
 	}
 	return metadata.NewIncomingContext(ctx, md)
 }
+
+// stubActiveSession makes every session looked up by authorizeUser active
+func stubActiveSession(store *mockdb.MockStore) {
+	store.EXPECT().
+		GetSessionAuthInfo(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(db.GetSessionAuthInfoRow{ExpiresAt: time.Now().Add(time.Hour)}, nil)
+}
+
+// stubDepositorOwner makes every account owner looked up by the system account check a depositor
+func stubDepositorOwner(store *mockdb.MockStore) {
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, username string) (db.Users, error) {
+			return db.Users{Username: username, Role: string(util.DepositorRole)}, nil
+		})
+}
+
+// stubVerifiedUser makes every user looked up by the policy check email verified
+func stubVerifiedUser(store *mockdb.MockStore) {
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, username string) (db.Users, error) {
+			return db.Users{Username: username, IsEmailVerified: true}, nil
+		})
+}
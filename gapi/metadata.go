@@ -4,6 +4,8 @@ import (
 	"context"
 	"log"
 
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 )
@@ -12,6 +14,7 @@ const (
 	grpcGatewayUserAgentHeader = "grpcgateway-user-agent"
 	userAgentHeader            = "user-agent"
 	xForwardedForHeader        = "x-forwarded-for"
+	idempotencyKeyHeader       = "idempotency-key"
 )
 
 type Metadata struct {
@@ -49,3 +52,24 @@ func (server *Server) extractMetadata(ctx context.Context) (*Metadata, error) {
 
 	return metaData, nil
 }
+
+// extractIdempotencyKey returns the idempotency key sent by the client, or an empty string if there is none
+func extractIdempotencyKey(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if key := md.Get(idempotencyKeyHeader); len(key) > 0 {
+			return key[0]
+		}
+	}
+	return ""
+}
+
+// validateIdempotencyKey reports a malformed idempotency key header. An empty key is allowed.
+func validateIdempotencyKey(key string) (violations []*errdetails.BadRequest_FieldViolation) {
+	if key == "" {
+		return nil
+	}
+	if err := util.ValidateIdempotencyKey(key); err != nil {
+		violations = append(violations, fieldViolations(idempotencyKeyHeader, err))
+	}
+	return violations
+}
@@ -0,0 +1,27 @@
+package gapi
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+)
+
+// encodePageToken turns the id of the last row of a page into the opaque token that fetches the next page
+func encodePageToken(lastID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(lastID, 10)))
+}
+
+// decodePageToken returns the id the next page starts after
+func decodePageToken(token string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errors.New("invalid page token")
+	}
+
+	lastID, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || lastID <= 0 {
+		return 0, errors.New("invalid page token")
+	}
+
+	return lastID, nil
+}
@@ -0,0 +1,47 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/guncv/Simple-Bank/policy"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// enforcePolicy loads the user and rejects the action if policy forbids it
+func (server *Server) enforcePolicy(ctx context.Context, username string, action policy.Action) error {
+	user, err := server.store.GetUser(ctx, username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return status.Errorf(codes.NotFound, "user not found")
+		}
+		return status.Errorf(codes.Internal, "failed to get user: %s", err)
+	}
+
+	if err := policy.Check(user, action); err != nil {
+		return policyError(username, err)
+	}
+	return nil
+}
+
+func policyError(username string, err error) error {
+	violation := &errdetails.PreconditionFailure_Violation{
+		Subject:     "users/" + username,
+		Description: err.Error(),
+	}
+	if errors.Is(err, policy.ErrEmailNotVerified) {
+		violation.Type = policy.ViolationEmailNotVerified
+	}
+
+	statusFailed := status.New(codes.FailedPrecondition, err.Error())
+	details, detailsErr := statusFailed.WithDetails(&errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{violation},
+	})
+	if detailsErr != nil {
+		return statusFailed.Err()
+	}
+	return details.Err()
+}
@@ -0,0 +1,72 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CloseAccount(ctx context.Context, req *pb.CloseAccountRequest) (*pb.CloseAccountResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []util.Role{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCloseAccountRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	if authPayload.Role != util.BankerRole && account.Owner != authPayload.Username {
+		return nil, permissionDeniedError(errors.New("account doesn't belong to the authenticated user"))
+	}
+
+	if err := server.checkCustomerAccount(ctx, account); err != nil {
+		return nil, err
+	}
+
+	if account.Status != db.AccountStatusActive {
+		return nil, status.Errorf(codes.FailedPrecondition, "account [%d] is %s", account.ID, account.Status)
+	}
+
+	if account.Balance != 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "account [%d] balance must be zero to close it", account.ID)
+	}
+
+	account, err = server.store.CloseAccount(ctx, req.GetId())
+	if err != nil {
+		// the balance or status changed between the read and the update
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.FailedPrecondition, "account [%d] can no longer be closed", req.GetId())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to close account: %s", err)
+	}
+
+	resp := &pb.CloseAccountResponse{
+		Account: convertAccount(server.currencies, account),
+	}
+
+	return resp, nil
+}
+
+func validateCloseAccountRequest(req *pb.CloseAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := util.ValidateAccountId(req.GetId()); err != nil {
+		violations = append(violations, fieldViolations("id", err))
+	}
+	return violations
+}
@@ -0,0 +1,57 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []util.Role{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCreateAccountRequest(req, server.currencies)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	arg := db.CreateAccountParams{
+		Owner:    authPayload.Username,
+		Balance:  0,
+		Currency: req.GetCurrency(),
+	}
+
+	account, err := server.store.CreateAccount(ctx, arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				return nil, status.Errorf(codes.AlreadyExists, "account with this currency already exists: %s", err)
+			case "foreign_key_violation":
+				return nil, status.Errorf(codes.NotFound, "owner not found: %s", err)
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create account: %s", err)
+	}
+
+	resp := &pb.CreateAccountResponse{
+		Account: convertAccount(server.currencies, account),
+	}
+
+	return resp, nil
+}
+
+func validateCreateAccountRequest(req *pb.CreateAccountRequest, currencies util.Currencies) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := currencies.Validate(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolations("currency", err))
+	}
+	return violations
+}
@@ -0,0 +1,124 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/policy"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateExchangeTransfer(ctx context.Context, req *pb.CreateExchangeTransferRequest) (*pb.CreateExchangeTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []util.Role{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	idempotencyKey := extractIdempotencyKey(ctx)
+
+	violations := validateCreateExchangeTransferRequest(req)
+	violations = append(violations, validateIdempotencyKey(idempotencyKey)...)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if err := server.enforcePolicy(ctx, authPayload.Username, policy.ActionTransfer); err != nil {
+		return nil, err
+	}
+
+	fromAccount, err := server.store.GetAccount(ctx, req.GetFromAccountId())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	if fromAccount.Owner != authPayload.Username {
+		return nil, permissionDeniedError(errors.New("from account doesn't belong to the authenticated user"))
+	}
+
+	arg := db.ExchangeTransferTxParams{
+		FromAccountID:  req.GetFromAccountId(),
+		ToAccountID:    req.GetToAccountId(),
+		Amount:         req.GetAmount(),
+		IdempotencyKey: idempotencyKey,
+		Currencies:     server.currencies,
+	}
+	if req.QuoteId != nil {
+		arg.QuoteID = uuid.NullUUID{UUID: uuid.MustParse(req.GetQuoteId()), Valid: true}
+	}
+
+	result, err := server.store.ExchangeTransferTx(ctx, arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		case errors.Is(err, db.ErrIdempotencyKeyConflict):
+			return nil, status.Errorf(codes.AlreadyExists, "%s", err)
+		case errors.Is(err, db.ErrSameCurrency):
+			return nil, status.Errorf(codes.InvalidArgument, "%s, use CreateTransfer instead", err)
+		case errors.Is(err, db.ErrExchangeRateNotFound):
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		case errors.Is(err, db.ErrAmountTooSmall):
+			return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+		case errors.Is(err, db.ErrInsufficientFunds):
+			return nil, status.Errorf(codes.FailedPrecondition, "insufficient funds in account [%d]", fromAccount.ID)
+		case errors.Is(err, db.ErrAccountNotActive):
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		case errors.Is(err, db.ErrSystemAccount):
+			return nil, permissionDeniedError(err)
+		case errors.Is(err, db.ErrTransferLimitExceeded):
+			return nil, status.Errorf(codes.ResourceExhausted, "%s", err)
+		case errors.Is(err, db.ErrQuoteNotFound):
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		case errors.Is(err, db.ErrQuoteMismatch):
+			return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to exchange money: %s", err)
+	}
+
+	resp := &pb.CreateExchangeTransferResponse{
+		DebitTransfer:   convertTransfer(server.currencies, result.DebitTransfer, result.FromAccount.Currency),
+		CreditTransfer:  convertTransfer(server.currencies, result.CreditTransfer, result.ToAccount.Currency),
+		FromAccount:     convertAccount(server.currencies, result.FromAccount),
+		ToAccount:       convertAccount(server.currencies, result.ToAccount),
+		FromEntry:       convertEntry(server.currencies, result.FromEntry, result.FromAccount.Currency),
+		ToEntry:         convertEntry(server.currencies, result.ToEntry, result.ToAccount.Currency),
+		Rate:            result.Rate,
+		ConvertedAmount: result.ConvertedAmount,
+	}
+	if result.DebitTransfer.Fee > 0 {
+		resp.FeeEntry = convertEntry(server.currencies, result.FeeEntry, result.FromAccount.Currency)
+	}
+
+	return resp, nil
+}
+
+func validateCreateExchangeTransferRequest(req *pb.CreateExchangeTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := util.ValidateAccountId(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolations("from_account_id", err))
+	}
+	if err := util.ValidateAccountId(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolations("to_account_id", err))
+	}
+	if req.GetFromAccountId() == req.GetToAccountId() {
+		violations = append(violations, fieldViolations("to_account_id", errors.New("must differ from from_account_id")))
+	}
+	if err := util.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, fieldViolations("amount", err))
+	}
+	if req.QuoteId != nil {
+		if err := util.ValidateQuoteID(req.GetQuoteId()); err != nil {
+			violations = append(violations, fieldViolations("quote_id", err))
+		}
+	}
+	return violations
+}
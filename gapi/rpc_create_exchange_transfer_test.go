@@ -0,0 +1,284 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/token"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateExchangeTransferAPI(t *testing.T) {
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+
+	fromAccount := randomAccount(user1.Username)
+	fromAccount.ID = 1
+	fromAccount.Currency = util.USD
+	toAccount := randomAccount(user2.Username)
+	toAccount.ID = 2
+	toAccount.Currency = util.EUR
+
+	req := &pb.CreateExchangeTransferRequest{
+		FromAccountId: fromAccount.ID,
+		ToAccountId:   toAccount.ID,
+		Amount:        100,
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.CreateExchangeTransferRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.CreateExchangeTransferResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  req,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ExchangeTransferTxParams{
+					FromAccountID: fromAccount.ID,
+					ToAccountID:   toAccount.ID,
+					Amount:        100,
+					Currencies:    util.NewDefaultCurrencies(),
+				}
+				result := db.ExchangeTransferTxResult{
+					FromAccount:     fromAccount,
+					ToAccount:       toAccount,
+					Rate:            "0.92000000",
+					ConvertedAmount: 92,
+				}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().ExchangeTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateExchangeTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "0.92000000", res.GetRate())
+				require.Equal(t, int64(92), res.GetConvertedAmount())
+			},
+		},
+		{
+			name: "IdempotencyKey",
+			req:  req,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ExchangeTransferTxParams{
+					FromAccountID:  fromAccount.ID,
+					ToAccountID:    toAccount.ID,
+					Amount:         100,
+					IdempotencyKey: "exchange-key",
+					Currencies:     util.NewDefaultCurrencies(),
+				}
+				result := db.ExchangeTransferTxResult{
+					DebitTransfer:   db.Transfers{ID: 1, FromAccountID: fromAccount.ID, Amount: 100},
+					FromAccount:     fromAccount,
+					ToAccount:       toAccount,
+					Rate:            "0.92000000",
+					ConvertedAmount: 92,
+					Replayed:        true,
+				}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().ExchangeTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx := newContextWithBearerToken(t, tokenMaker, user1.Username, util.DepositorRole, time.Minute)
+				return newContextWithIdempotencyKey(ctx, "exchange-key")
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateExchangeTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(1), res.GetDebitTransfer().GetId())
+				require.Equal(t, int64(92), res.GetConvertedAmount())
+			},
+		},
+		{
+			name: "IdempotencyKeyConflict",
+			req:  req,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().ExchangeTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ExchangeTransferTxResult{}, db.ErrIdempotencyKeyConflict)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx := newContextWithBearerToken(t, tokenMaker, user1.Username, util.DepositorRole, time.Minute)
+				return newContextWithIdempotencyKey(ctx, "exchange-key")
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateExchangeTransferResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.AlreadyExists, status.Code(err))
+			},
+		},
+		{
+			name: "NotOwner",
+			req:  req,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().ExchangeTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user2.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateExchangeTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "RateNotFound",
+			req:  req,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().ExchangeTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ExchangeTransferTxResult{}, db.ErrExchangeRateNotFound)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateExchangeTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "SameCurrency",
+			req:  req,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().ExchangeTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ExchangeTransferTxResult{}, db.ErrSameCurrency)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateExchangeTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "SameAccount",
+			req: &pb.CreateExchangeTransferRequest{
+				FromAccountId: fromAccount.ID,
+				ToAccountId:   fromAccount.ID,
+				Amount:        100,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ExchangeTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateExchangeTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubActiveSession(store)
+			stubVerifiedUser(store)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.CreateExchangeTransfer(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestSetExchangeRateAPI(t *testing.T) {
+	banker, _ := randomUser(t)
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name         string
+		req          *pb.SetExchangeRateRequest
+		role         util.Role
+		username     string
+		buildStubs   func(store *mockdb.MockStore)
+		expectedCode codes.Code
+	}{
+		{
+			name:     "OK",
+			req:      &pb.SetExchangeRateRequest{FromCurrency: util.USD, ToCurrency: util.EUR, Rate: "0.92"},
+			role:     util.BankerRole,
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateExchangeRate(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateExchangeRateParams) (db.ExchangeRates, error) {
+						require.Equal(t, banker.Username, arg.CreatedBy)
+						require.Equal(t, "0.92", arg.Rate)
+						return db.ExchangeRates{ID: 1, FromCurrency: arg.FromCurrency, ToCurrency: arg.ToCurrency, Rate: arg.Rate}, nil
+					})
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:     "DepositorCannotSetRate",
+			req:      &pb.SetExchangeRateRequest{FromCurrency: util.USD, ToCurrency: util.EUR, Rate: "0.92"},
+			role:     util.DepositorRole,
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateExchangeRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:     "InvalidRate",
+			req:      &pb.SetExchangeRateRequest{FromCurrency: util.USD, ToCurrency: util.EUR, Rate: "0"},
+			role:     util.BankerRole,
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateExchangeRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:     "SameCurrency",
+			req:      &pb.SetExchangeRateRequest{FromCurrency: util.USD, ToCurrency: util.USD, Rate: "1"},
+			role:     util.BankerRole,
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateExchangeRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubActiveSession(store)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := newContextWithBearerToken(t, server.tokenMaker, tc.username, tc.role, time.Minute)
+			_, err := server.SetExchangeRate(ctx, tc.req)
+			require.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
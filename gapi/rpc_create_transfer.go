@@ -0,0 +1,144 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/policy"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateTransfer(ctx context.Context, req *pb.CreateTransferRequest) (*pb.CreateTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []util.Role{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	idempotencyKey := extractIdempotencyKey(ctx)
+
+	violations := validateCreateTransferRequest(req, server.currencies)
+	violations = append(violations, validateIdempotencyKey(idempotencyKey)...)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if err := server.enforcePolicy(ctx, authPayload.Username, policy.ActionTransfer); err != nil {
+		return nil, err
+	}
+
+	fromAccount, err := server.validAccount(ctx, req.GetFromAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	if fromAccount.Owner != authPayload.Username {
+		return nil, permissionDeniedError(errors.New("from account doesn't belong to the authenticated user"))
+	}
+
+	_, err = server.validAccount(ctx, req.GetToAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.TransferTxParams{
+		FromAccountID:  req.GetFromAccountId(),
+		ToAccountID:    req.GetToAccountId(),
+		Amount:         req.GetAmount(),
+		IdempotencyKey: idempotencyKey,
+	}
+	if req.QuoteId != nil {
+		arg.QuoteID = uuid.NullUUID{UUID: uuid.MustParse(req.GetQuoteId()), Valid: true}
+	}
+
+	result, err := server.store.TransferTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		if errors.Is(err, db.ErrIdempotencyKeyConflict) {
+			return nil, status.Errorf(codes.AlreadyExists, "%s", err)
+		}
+		if errors.Is(err, db.ErrInsufficientFunds) {
+			return nil, status.Errorf(codes.FailedPrecondition, "insufficient funds in account [%d]", fromAccount.ID)
+		}
+		if errors.Is(err, db.ErrAccountNotActive) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		if errors.Is(err, db.ErrSystemAccount) {
+			return nil, permissionDeniedError(err)
+		}
+		if errors.Is(err, db.ErrTransferLimitExceeded) {
+			return nil, status.Errorf(codes.ResourceExhausted, "%s", err)
+		}
+		if errors.Is(err, db.ErrQuoteNotFound) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		if errors.Is(err, db.ErrQuoteMismatch) {
+			return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to transfer money: %s", err)
+	}
+
+	resp := &pb.CreateTransferResponse{
+		Transfer:    convertTransfer(server.currencies, result.Transfer, result.FromAccount.Currency),
+		FromAccount: convertAccount(server.currencies, result.FromAccount),
+		ToAccount:   convertAccount(server.currencies, result.ToAccount),
+		FromEntry:   convertEntry(server.currencies, result.FromEntry, result.FromAccount.Currency),
+		ToEntry:     convertEntry(server.currencies, result.ToEntry, result.ToAccount.Currency),
+	}
+	if result.Transfer.Fee > 0 {
+		resp.FeeEntry = convertEntry(server.currencies, result.FeeEntry, result.FromAccount.Currency)
+	}
+
+	return resp, nil
+}
+
+// validAccount checks that the account exists and holds the given currency
+func (server *Server) validAccount(ctx context.Context, accountID int64, currency string) (db.Accounts, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return account, status.Errorf(codes.NotFound, "account [%d] not found", accountID)
+		}
+		return account, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	if account.Currency != currency {
+		violation := fieldViolations("currency", fmt.Errorf("account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, currency))
+		return account, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{violation})
+	}
+
+	return account, nil
+}
+
+func validateCreateTransferRequest(req *pb.CreateTransferRequest, currencies util.Currencies) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := util.ValidateAccountId(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolations("from_account_id", err))
+	}
+	if err := util.ValidateAccountId(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolations("to_account_id", err))
+	}
+	if req.GetFromAccountId() == req.GetToAccountId() {
+		violations = append(violations, fieldViolations("to_account_id", errors.New("must be different from from_account_id")))
+	}
+	if err := util.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, fieldViolations("amount", err))
+	}
+	if err := currencies.Validate(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolations("currency", err))
+	}
+	if req.QuoteId != nil {
+		if err := util.ValidateQuoteID(req.GetQuoteId()); err != nil {
+			violations = append(violations, fieldViolations("quote_id", err))
+		}
+	}
+	return violations
+}
@@ -3,13 +3,11 @@ package gapi
 import (
 	"context"
 	"log"
-	"time"
 
 	db "github.com/guncv/Simple-Bank/db/sqlc"
 	"github.com/guncv/Simple-Bank/pb"
 	"github.com/guncv/Simple-Bank/util"
 	"github.com/guncv/Simple-Bank/worker"
-	"github.com/hibiken/asynq"
 	"github.com/lib/pq"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
@@ -35,21 +33,11 @@ func (server *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest)
 			FullName:       req.GetFullName(),
 			Email:          req.GetEmail(),
 		},
-		AfterCreate: func(user db.Users) error {
+		AfterCreate: func(user db.Users) (db.CreateOutboxMessageParams, error) {
 			taskPayload := &worker.PayloadSendVerifyEmail{
 				Username: user.Username,
 			}
-
-			opts := []asynq.Option{
-				asynq.MaxRetry(10),
-				asynq.Queue(worker.QueueCritical),
-				asynq.ProcessIn(10 * time.Second),
-			}
-			if err := server.taskDistributor.DistributeTaskSendVerifyEmail(ctx, taskPayload, opts...); err != nil {
-				log.Printf("failed to distribute task: %s", err)
-				return err
-			}
-			return nil
+			return worker.NewOutboxMessage(worker.TaskSendVerifyEmail, taskPayload, worker.QueueCritical, 10)
 		},
 	}
 
@@ -3,6 +3,7 @@ package gapi
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"testing"
@@ -56,8 +57,19 @@ func (e eqCreateUserTxParamsMatcher) Matches(x interface{}) bool {
 		return false
 	}
 
-	actualArg.AfterCreate(e.user)
-	return true
+	// the verification email is queued through the outbox in the same transaction
+	message, err := actualArg.AfterCreate(e.user)
+	if err != nil {
+		return false
+	}
+
+	var payload worker.PayloadSendVerifyEmail
+	if err := json.Unmarshal(message.Payload, &payload); err != nil {
+		return false
+	}
+	return message.TaskType == worker.TaskSendVerifyEmail &&
+		message.Queue == worker.QueueCritical &&
+		payload.Username == e.user.Username
 }
 
 func (e eqCreateUserTxParamsMatcher) String() string {
@@ -92,24 +104,12 @@ func TestCreateUserAPI(t *testing.T) {
 						FullName: user.FullName,
 						Email:    user.Email,
 					},
-					AfterCreate: func(user db.Users) error {
-						return nil
-					},
-				}
-
-				taskPayload := &worker.PayloadSendVerifyEmail{
-					Username: user.Username,
 				}
 
 				store.EXPECT().
 					CreateUserTx(gomock.Any(), EqCreateUserTxParams(arg, password, user)).
 					Times(1).
 					Return(db.CreateUserTxResult{User: user}, nil)
-
-				taskDistributor.EXPECT().
-					DistributeTaskSendVerifyEmail(gomock.Any(), taskPayload, gomock.Any()).
-					Times(1).
-					Return(nil)
 			},
 			checkResponse: func(t *testing.T, p *pb.CreateUserResponse, err error) {
 				require.NoError(t, err)
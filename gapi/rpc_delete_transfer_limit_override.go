@@ -0,0 +1,44 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) DeleteTransferLimitOverride(ctx context.Context, req *pb.DeleteTransferLimitOverrideRequest) (*pb.DeleteTransferLimitOverrideResponse, error) {
+	_, err := server.authorizeUser(ctx, []util.Role{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateDeleteTransferLimitOverrideRequest(req, server.currencies)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	err = server.store.DeleteTransferLimitOverride(ctx, db.DeleteTransferLimitOverrideParams{
+		Username: req.GetUsername(),
+		Currency: req.GetCurrency(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete transfer limit override: %s", err)
+	}
+
+	return &pb.DeleteTransferLimitOverrideResponse{}, nil
+}
+
+func validateDeleteTransferLimitOverrideRequest(req *pb.DeleteTransferLimitOverrideRequest, currencies util.Currencies) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := util.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolations("username", err))
+	}
+	if err := currencies.Validate(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolations("currency", err))
+	}
+	return violations
+}
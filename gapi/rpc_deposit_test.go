@@ -0,0 +1,203 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/token"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDepositAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	banker, _ := randomUser(t)
+
+	account := randomAccount(user.Username)
+	amount := int64(100)
+
+	testCases := []struct {
+		name          string
+		req           *pb.DepositRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.DepositResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.DepositRequest{
+				AccountId: account.ID,
+				Amount:    amount,
+				Currency:  account.Currency,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CashTxParams{
+					AccountID:   account.ID,
+					Amount:      amount,
+					InitiatedBy: banker.Username,
+				}
+				updatedAccount := account
+				updatedAccount.Balance += amount
+				result := db.CashTxResult{
+					Transfer: db.Transfers{
+						ID:          1,
+						ToAccountID: account.ID,
+						Amount:      amount,
+						InitiatedBy: sql.NullString{String: banker.Username, Valid: true},
+					},
+					Account: updatedAccount,
+					Entry:   db.Entries{ID: 1, AccountID: account.ID, Amount: amount},
+				}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.DepositResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, banker.Username, res.GetTransfer().GetInitiatedBy())
+				require.Equal(t, account.Balance+amount, res.GetAccount().GetBalance())
+				require.Equal(t, amount, res.GetEntry().GetAmount())
+			},
+		},
+		{
+			name: "DepositorCannotDeposit",
+			req: &pb.DepositRequest{
+				AccountId: account.ID,
+				Amount:    amount,
+				Currency:  account.Currency,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.DepositResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "AccountNotActive",
+			req: &pb.DepositRequest{
+				AccountId: account.ID,
+				Amount:    amount,
+				Currency:  account.Currency,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CashTxResult{}, db.ErrAccountNotActive)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.DepositResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidAmount",
+			req: &pb.DepositRequest{
+				AccountId: account.ID,
+				Amount:    -amount,
+				Currency:  account.Currency,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.DepositResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubActiveSession(store)
+			stubDepositorOwner(store)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.Deposit(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestWithdrawAPIInsufficientFunds(t *testing.T) {
+	user, _ := randomUser(t)
+	banker, _ := randomUser(t)
+	account := randomAccount(user.Username)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	stubActiveSession(store)
+	stubDepositorOwner(store)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().WithdrawTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CashTxResult{}, db.ErrInsufficientFunds)
+
+	server := newTestServer(t, store, nil)
+	ctx := newContextWithBearerToken(t, server.tokenMaker, banker.Username, util.BankerRole, time.Minute)
+	res, err := server.Withdraw(ctx, &pb.WithdrawRequest{
+		AccountId: account.ID,
+		Amount:    account.Balance + 1,
+		Currency:  account.Currency,
+	})
+	require.Error(t, err)
+	require.Nil(t, res)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestDepositAPISystemAccount(t *testing.T) {
+	banker, _ := randomUser(t)
+	account := randomAccount(db.CashAccountOwner)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	stubActiveSession(store)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(db.CashAccountOwner)).
+		Times(1).
+		Return(db.Users{Username: db.CashAccountOwner, Role: string(util.SystemRole)}, nil)
+	store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store, nil)
+	ctx := newContextWithBearerToken(t, server.tokenMaker, banker.Username, util.BankerRole, time.Minute)
+	res, err := server.Deposit(ctx, &pb.DepositRequest{
+		AccountId: account.ID,
+		Amount:    100,
+		Currency:  account.Currency,
+	})
+	require.Error(t, err)
+	require.Nil(t, res)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
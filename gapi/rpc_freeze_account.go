@@ -0,0 +1,40 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server *Server) FreezeAccount(ctx context.Context, req *pb.FreezeAccountRequest) (*pb.FreezeAccountResponse, error) {
+	_, err := server.authorizeUser(ctx, []util.Role{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateFreezeAccountRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.changeAccountStatus(ctx, req.GetId(), db.AccountStatusActive, db.AccountStatusFrozen)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.FreezeAccountResponse{
+		Account: convertAccount(server.currencies, account),
+	}
+
+	return resp, nil
+}
+
+func validateFreezeAccountRequest(req *pb.FreezeAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := util.ValidateAccountId(req.GetId()); err != nil {
+		violations = append(violations, fieldViolations("id", err))
+	}
+	return violations
+}
@@ -0,0 +1,158 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/token"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFreezeAccountAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	banker, _ := randomUser(t)
+
+	account := randomAccount(user.Username)
+	frozenAccount := account
+	frozenAccount.Status = db.AccountStatusFrozen
+
+	testCases := []struct {
+		name          string
+		req           *pb.FreezeAccountRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.FreezeAccountResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.FreezeAccountRequest{Id: account.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateAccountStatusParams{
+					ID:         account.ID,
+					FromStatus: db.AccountStatusActive,
+					ToStatus:   db.AccountStatusFrozen,
+				}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().UpdateAccountStatus(gomock.Any(), gomock.Eq(arg)).Times(1).Return(frozenAccount, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.FreezeAccountResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, string(db.AccountStatusFrozen), res.GetAccount().GetStatus())
+			},
+		},
+		{
+			name: "AlreadyFrozen",
+			req:  &pb.FreezeAccountRequest{Id: account.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(frozenAccount, nil)
+				store.EXPECT().UpdateAccountStatus(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.FreezeAccountResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "DepositorCannotFreeze",
+			req:  &pb.FreezeAccountRequest{Id: account.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdateAccountStatus(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.FreezeAccountResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubActiveSession(store)
+			stubDepositorOwner(store)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.FreezeAccount(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestUnfreezeAccountAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	banker, _ := randomUser(t)
+
+	account := randomAccount(user.Username)
+	frozenAccount := account
+	frozenAccount.Status = db.AccountStatusFrozen
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	stubActiveSession(store)
+	stubDepositorOwner(store)
+
+	arg := db.UpdateAccountStatusParams{
+		ID:         account.ID,
+		FromStatus: db.AccountStatusFrozen,
+		ToStatus:   db.AccountStatusActive,
+	}
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(frozenAccount, nil)
+	store.EXPECT().UpdateAccountStatus(gomock.Any(), gomock.Eq(arg)).Times(1).Return(account, nil)
+
+	server := newTestServer(t, store, nil)
+	ctx := newContextWithBearerToken(t, server.tokenMaker, banker.Username, util.BankerRole, time.Minute)
+	res, err := server.UnfreezeAccount(ctx, &pb.UnfreezeAccountRequest{Id: account.ID})
+	require.NoError(t, err)
+	require.Equal(t, string(db.AccountStatusActive), res.GetAccount().GetStatus())
+}
+
+func TestFreezeAccountAPISystemAccount(t *testing.T) {
+	banker, _ := randomUser(t)
+	account := randomAccount(db.RevenueAccountOwner)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	stubActiveSession(store)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(db.RevenueAccountOwner)).
+		Times(1).
+		Return(db.Users{Username: db.RevenueAccountOwner, Role: string(util.SystemRole)}, nil)
+	store.EXPECT().UpdateAccountStatus(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store, nil)
+	ctx := newContextWithBearerToken(t, server.tokenMaker, banker.Username, util.BankerRole, time.Minute)
+	res, err := server.FreezeAccount(ctx, &pb.FreezeAccountRequest{Id: account.ID})
+	require.Error(t, err)
+	require.Nil(t, res)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,74 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetLimits(ctx context.Context, req *pb.GetLimitsRequest) (*pb.GetLimitsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []util.Role{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateGetLimitsRequest(req, server.currencies)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	username := authPayload.Username
+	if req.Username != nil {
+		if authPayload.Role != util.BankerRole && req.GetUsername() != authPayload.Username {
+			return nil, permissionDeniedError(errors.New("cannot get limits of another user"))
+		}
+		username = req.GetUsername()
+	}
+
+	user, err := server.store.GetUser(ctx, username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get user: %s", err)
+	}
+
+	currencies := server.currencies.Codes()
+	if req.Currency != nil {
+		currencies = []string{req.GetCurrency()}
+	}
+
+	resp := &pb.GetLimitsResponse{
+		Username: user.Username,
+		Limits:   make([]*pb.TransferLimit, 0, len(currencies)),
+	}
+	for _, currency := range currencies {
+		limit, err := server.transferLimit(ctx, user, currency)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get limits: %s", err)
+		}
+		resp.Limits = append(resp.Limits, limit)
+	}
+
+	return resp, nil
+}
+
+func validateGetLimitsRequest(req *pb.GetLimitsRequest, currencies util.Currencies) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.Username != nil {
+		if err := util.ValidateUsername(req.GetUsername()); err != nil {
+			violations = append(violations, fieldViolations("username", err))
+		}
+	}
+	if req.Currency != nil {
+		if err := currencies.Validate(req.GetCurrency()); err != nil {
+			violations = append(violations, fieldViolations("currency", err))
+		}
+	}
+	return violations
+}
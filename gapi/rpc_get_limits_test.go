@@ -0,0 +1,294 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/token"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetLimitsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+	currency := util.USD
+
+	testCases := []struct {
+		name          string
+		req           *pb.GetLimitsRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.GetLimitsResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.GetLimitsRequest{Currency: &currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEffectiveTransferLimit(gomock.Any(), gomock.Eq(db.GetEffectiveTransferLimitParams{
+						Username: user.Username,
+						Currency: util.USD,
+						Role:     user.Role,
+					})).
+					Times(1).
+					Return(db.GetEffectiveTransferLimitRow{
+						PerTransaction: sql.NullInt64{Int64: 500, Valid: true},
+						Daily:          sql.NullInt64{Int64: 1000, Valid: true},
+					}, nil)
+				store.EXPECT().
+					GetTransferTotals(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetTransferTotalsRow{DailyTotal: 1200, MonthlyTotal: 3000}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetLimitsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetLimits(), 1)
+
+				limit := res.GetLimits()[0]
+				require.Equal(t, util.USD, limit.GetCurrency())
+				require.Equal(t, int64(500), limit.GetPerTransaction())
+				require.Equal(t, int64(1000), limit.GetDaily())
+				require.Equal(t, int64(1200), limit.GetDailyUsed())
+				require.Equal(t, int64(0), limit.GetDailyRemaining())
+				require.Nil(t, limit.Monthly)
+				require.Nil(t, limit.MonthlyRemaining)
+			},
+		},
+		{
+			name: "AllCurrenciesWithoutLimits",
+			req:  &pb.GetLimitsRequest{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetEffectiveTransferLimit(gomock.Any(), gomock.Any()).
+					Times(len(util.NewDefaultCurrencies())).
+					Return(db.GetEffectiveTransferLimitRow{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetTransferTotals(gomock.Any(), gomock.Any()).
+					Times(len(util.NewDefaultCurrencies())).
+					Return(db.GetTransferTotalsRow{}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetLimitsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetLimits(), len(util.NewDefaultCurrencies()))
+				for _, limit := range res.GetLimits() {
+					require.Nil(t, limit.Daily)
+					require.False(t, limit.GetOverridden())
+				}
+			},
+		},
+		{
+			name: "BankerGetsOtherUser",
+			req:  &pb.GetLimitsRequest{Username: &other.Username, Currency: &currency},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(other.Username)).Times(1).Return(other, nil)
+				store.EXPECT().
+					GetEffectiveTransferLimit(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetEffectiveTransferLimitRow{Overridden: true}, nil)
+				store.EXPECT().GetTransferTotals(gomock.Any(), gomock.Any()).Times(1).Return(db.GetTransferTotalsRow{}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetLimitsResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, other.Username, res.GetUsername())
+				require.True(t, res.GetLimits()[0].GetOverridden())
+			},
+		},
+		{
+			name: "DepositorCannotGetOtherUser",
+			req:  &pb.GetLimitsRequest{Username: &other.Username},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetLimitsResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubActiveSession(store)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.GetLimits(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestSetTransferLimitAPI(t *testing.T) {
+	banker, _ := randomUser(t)
+	user, _ := randomUser(t)
+	user.IsEmailVerified = true
+	unverifiedUser, _ := randomUser(t)
+	unverifiedUser.IsEmailVerified = false
+
+	role := string(util.DepositorRole)
+	daily := int64(1000)
+	negative := int64(-1)
+
+	testCases := []struct {
+		name          string
+		req           *pb.SetTransferLimitRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.SetTransferLimitResponse, err error)
+	}{
+		{
+			name: "RoleLimit",
+			req:  &pb.SetTransferLimitRequest{Role: &role, Currency: util.USD, Daily: &daily},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpsertTransferLimitParams{
+					Role:      role,
+					Currency:  util.USD,
+					Daily:     sql.NullInt64{Int64: daily, Valid: true},
+					UpdatedBy: banker.Username,
+				}
+				store.EXPECT().UpsertTransferLimit(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferLimits{}, nil)
+				store.EXPECT().UpsertTransferLimitOverride(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.SetTransferLimitResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, daily, res.GetLimit().GetDaily())
+				require.Nil(t, res.GetLimit().PerTransaction)
+			},
+		},
+		{
+			name: "UserOverride",
+			req:  &pb.SetTransferLimitRequest{Username: &user.Username, Currency: util.USD, Daily: &daily},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				arg := db.UpsertTransferLimitOverrideParams{
+					Username:  user.Username,
+					Currency:  util.USD,
+					Daily:     sql.NullInt64{Int64: daily, Valid: true},
+					UpdatedBy: banker.Username,
+				}
+				store.EXPECT().UpsertTransferLimitOverride(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferLimitOverrides{}, nil)
+				store.EXPECT().
+					GetEffectiveTransferLimit(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(db.GetEffectiveTransferLimitRow{Daily: arg.Daily, Overridden: true}, nil)
+				store.EXPECT().
+					GetTransferTotals(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetTransferTotalsRow{DailyTotal: 400}, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.SetTransferLimitResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetLimit().GetOverridden())
+				require.Equal(t, int64(600), res.GetLimit().GetDailyRemaining())
+			},
+		},
+		{
+			name: "UnverifiedUser",
+			req:  &pb.SetTransferLimitRequest{Username: &unverifiedUser.Username, Currency: util.USD, Daily: &daily},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(unverifiedUser.Username)).Times(1).Return(unverifiedUser, nil)
+				store.EXPECT().
+					GetEffectiveTransferLimit(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetEffectiveTransferLimitRow{Daily: sql.NullInt64{Int64: daily / 2, Valid: true}}, nil)
+				store.EXPECT().UpsertTransferLimitOverride(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.SetTransferLimitResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "UnverifiedUserLowerLimit",
+			req:  &pb.SetTransferLimitRequest{Username: &unverifiedUser.Username, Currency: util.USD, Daily: &daily},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(unverifiedUser.Username)).Times(1).Return(unverifiedUser, nil)
+				current := db.GetEffectiveTransferLimitRow{Daily: sql.NullInt64{Int64: daily * 2, Valid: true}}
+				lowered := db.GetEffectiveTransferLimitRow{Daily: sql.NullInt64{Int64: daily, Valid: true}, Overridden: true}
+				gomock.InOrder(
+					store.EXPECT().GetEffectiveTransferLimit(gomock.Any(), gomock.Any()).Times(1).Return(current, nil),
+					store.EXPECT().UpsertTransferLimitOverride(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferLimitOverrides{}, nil),
+					store.EXPECT().GetEffectiveTransferLimit(gomock.Any(), gomock.Any()).Times(1).Return(lowered, nil),
+				)
+				store.EXPECT().
+					GetTransferTotals(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetTransferTotalsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.SetTransferLimitResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, daily, res.GetLimit().GetDaily())
+			},
+		},
+		{
+			name: "UsernameAndRole",
+			req:  &pb.SetTransferLimitRequest{Username: &user.Username, Role: &role, Currency: util.USD},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertTransferLimit(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpsertTransferLimitOverride(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.SetTransferLimitResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "NegativeLimit",
+			req:  &pb.SetTransferLimitRequest{Role: &role, Currency: util.USD, Monthly: &negative},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertTransferLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.SetTransferLimitResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubActiveSession(store)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := newContextWithBearerToken(t, server.tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			res, err := server.SetTransferLimit(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
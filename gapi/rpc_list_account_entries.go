@@ -0,0 +1,96 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListAccountEntries(ctx context.Context, req *pb.ListAccountEntriesRequest) (*pb.ListAccountEntriesResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []util.Role{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListAccountEntriesRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	if authPayload.Role != util.BankerRole && account.Owner != authPayload.Username {
+		return nil, permissionDeniedError(errors.New("account doesn't belong to the authenticated user"))
+	}
+
+	// Fetch one extra row to find out whether there is a next page
+	arg := db.ListAccountEntriesParams{
+		AccountID: account.ID,
+		Direction: req.GetDirection(),
+		PageSize:  req.GetPageSize() + 1,
+	}
+	if req.GetPageToken() != "" {
+		lastID, _ := decodePageToken(req.GetPageToken())
+		arg.BeforeID = sql.NullInt64{Int64: lastID, Valid: true}
+	}
+	if req.FromTime != nil {
+		arg.FromTime = sql.NullTime{Time: req.GetFromTime().AsTime(), Valid: true}
+	}
+	if req.ToTime != nil {
+		arg.ToTime = sql.NullTime{Time: req.GetToTime().AsTime(), Valid: true}
+	}
+
+	entries, err := server.store.ListAccountEntries(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list entries: %s", err)
+	}
+
+	resp := &pb.ListAccountEntriesResponse{}
+	if len(entries) > int(req.GetPageSize()) {
+		entries = entries[:req.GetPageSize()]
+		resp.NextPageToken = encodePageToken(entries[len(entries)-1].ID)
+	}
+
+	resp.Entries = make([]*pb.Entry, 0, len(entries))
+	for _, entry := range entries {
+		resp.Entries = append(resp.Entries, convertAccountEntry(server.currencies, entry, account.Currency))
+	}
+
+	return resp, nil
+}
+
+func validateListAccountEntriesRequest(req *pb.ListAccountEntriesRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := util.ValidateAccountId(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolations("account_id", err))
+	}
+	if req.FromTime != nil && req.ToTime != nil && !req.GetFromTime().AsTime().Before(req.GetToTime().AsTime()) {
+		violations = append(violations, fieldViolations("to_time", errors.New("must be after from_time")))
+	}
+	if req.Direction != nil {
+		if err := util.ValidateEntryDirection(req.GetDirection()); err != nil {
+			violations = append(violations, fieldViolations("direction", err))
+		}
+	}
+	if err := util.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolations("page_size", err))
+	}
+	if req.GetPageToken() != "" {
+		if _, err := decodePageToken(req.GetPageToken()); err != nil {
+			violations = append(violations, fieldViolations("page_token", err))
+		}
+	}
+	return violations
+}
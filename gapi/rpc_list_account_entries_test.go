@@ -0,0 +1,199 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/token"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func randomAccountEntries(account db.Accounts, n int) []db.ListAccountEntriesRow {
+	entries := make([]db.ListAccountEntriesRow, n)
+	balance := account.Balance
+	for i := range entries {
+		amount := util.RandomMoney()
+		entries[i] = db.ListAccountEntriesRow{
+			ID:           int64(100 - i),
+			AccountID:    account.ID,
+			Amount:       amount,
+			CreatedAt:    time.Now(),
+			BalanceAfter: balance,
+		}
+		balance -= amount
+	}
+	return entries
+}
+
+func TestListAccountEntriesAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+	account := randomAccount(user.Username)
+
+	pageSize := int32(5)
+	entries := randomAccountEntries(account, int(pageSize)+1)
+	direction := util.CreditDirection
+	invalidDirection := "sideways"
+
+	testCases := []struct {
+		name          string
+		req           *pb.ListAccountEntriesRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.ListAccountEntriesResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.ListAccountEntriesRequest{
+				AccountId: account.ID,
+				PageSize:  pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListAccountEntriesParams{
+					AccountID: account.ID,
+					PageSize:  pageSize + 1,
+				}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountEntries(gomock.Any(), gomock.Eq(arg)).Times(1).Return(entries, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountEntriesResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetEntries(), int(pageSize))
+				require.Equal(t, account.Balance, res.GetEntries()[0].GetBalanceAfter())
+
+				lastID, err := decodePageToken(res.GetNextPageToken())
+				require.NoError(t, err)
+				require.Equal(t, entries[pageSize-1].ID, lastID)
+			},
+		},
+		{
+			name: "NextPage",
+			req: &pb.ListAccountEntriesRequest{
+				AccountId: account.ID,
+				Direction: &direction,
+				PageSize:  pageSize,
+				PageToken: encodePageToken(entries[0].ID),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListAccountEntriesParams{
+					AccountID: account.ID,
+					BeforeID:  sql.NullInt64{Int64: entries[0].ID, Valid: true},
+					Direction: direction,
+					PageSize:  pageSize + 1,
+				}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountEntries(gomock.Any(), gomock.Eq(arg)).Times(1).Return(entries[1:], nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountEntriesResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetEntries(), int(pageSize))
+				require.Empty(t, res.GetNextPageToken())
+			},
+		},
+		{
+			name: "NotOwner",
+			req: &pb.ListAccountEntriesRequest{
+				AccountId: account.ID,
+				PageSize:  pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, other.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountEntriesResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "AccountNotFound",
+			req: &pb.ListAccountEntriesRequest{
+				AccountId: account.ID,
+				PageSize:  pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Accounts{}, sql.ErrNoRows)
+				store.EXPECT().ListAccountEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountEntriesResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidPageToken",
+			req: &pb.ListAccountEntriesRequest{
+				AccountId: account.ID,
+				PageSize:  pageSize,
+				PageToken: "not-a-token",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountEntriesResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidDirection",
+			req: &pb.ListAccountEntriesRequest{
+				AccountId: account.ID,
+				Direction: &invalidDirection,
+				PageSize:  pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountEntriesResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubActiveSession(store)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.ListAccountEntries(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []util.Role{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListAccountsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	owner := authPayload.Username
+	if req.Owner != nil {
+		if authPayload.Role != util.BankerRole && req.GetOwner() != authPayload.Username {
+			return nil, permissionDeniedError(errors.New("cannot list accounts of another user"))
+		}
+		owner = req.GetOwner()
+	}
+
+	arg := db.ListAccountParams{
+		Owner:  owner,
+		Limit:  req.GetPageSize(),
+		Offset: (req.GetPageId() - 1) * req.GetPageSize(),
+	}
+
+	accounts, err := server.store.ListAccount(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list accounts: %s", err)
+	}
+
+	resp := &pb.ListAccountsResponse{
+		Accounts: make([]*pb.Account, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		resp.Accounts = append(resp.Accounts, convertAccount(server.currencies, account))
+	}
+
+	return resp, nil
+}
+
+func validateListAccountsRequest(req *pb.ListAccountsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.Owner != nil {
+		if err := util.ValidateUsername(req.GetOwner()); err != nil {
+			violations = append(violations, fieldViolations("owner", err))
+		}
+	}
+	if err := util.ValidatePageID(req.GetPageId()); err != nil {
+		violations = append(violations, fieldViolations("page_id", err))
+	}
+	if err := util.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolations("page_size", err))
+	}
+	return violations
+}
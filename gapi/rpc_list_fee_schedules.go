@@ -0,0 +1,31 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListFeeSchedules(ctx context.Context, req *pb.ListFeeSchedulesRequest) (*pb.ListFeeSchedulesResponse, error) {
+	_, err := server.authorizeUser(ctx, []util.Role{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	schedules, err := server.store.ListFeeSchedules(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list fee schedules: %s", err)
+	}
+
+	resp := &pb.ListFeeSchedulesResponse{
+		FeeSchedules: make([]*pb.FeeSchedule, 0, len(schedules)),
+	}
+	for _, schedule := range schedules {
+		resp.FeeSchedules = append(resp.FeeSchedules, convertFeeSchedule(schedule))
+	}
+
+	return resp, nil
+}
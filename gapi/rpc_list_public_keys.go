@@ -0,0 +1,26 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/token"
+)
+
+func (server *Server) ListPublicKeys(ctx context.Context, req *pb.ListPublicKeysRequest) (*pb.ListPublicKeysResponse, error) {
+	resp := &pb.ListPublicKeysResponse{
+		Keys: []*pb.PublicKey{},
+	}
+
+	// symmetric makers have no key that can be shared
+	provider, ok := server.tokenMaker.(token.PublicKeyProvider)
+	if !ok {
+		return resp, nil
+	}
+
+	for _, key := range provider.PublicKeys() {
+		resp.Keys = append(resp.Keys, convertPublicKey(key))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,55 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []util.Role{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListSessionsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	username := authPayload.Username
+	if req.Username != nil {
+		if authPayload.Role != util.BankerRole && req.GetUsername() != authPayload.Username {
+			return nil, permissionDeniedError(errors.New("cannot list sessions of another user"))
+		}
+		username = req.GetUsername()
+	}
+
+	sessions, err := server.store.ListActiveSessions(ctx, username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list sessions: %s", err)
+	}
+
+	resp := &pb.ListSessionsResponse{
+		Sessions: make([]*pb.Session, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, convertSession(session))
+	}
+
+	return resp, nil
+}
+
+func validateListSessionsRequest(req *pb.ListSessionsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.Username != nil {
+		if err := util.ValidateUsername(req.GetUsername()); err != nil {
+			violations = append(violations, fieldViolations("username", err))
+		}
+	}
+	return violations
+}
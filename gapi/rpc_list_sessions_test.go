@@ -0,0 +1,182 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/token"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListSessionsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+	invalidUsername := "#invalid"
+
+	sessions := []db.Sessions{
+		{
+			ID:        uuid.New(),
+			Username:  user.Username,
+			UserAgent: "curl/8.0",
+			ClientIp:  "127.0.0.1",
+			CreatedAt: time.Now(),
+			ExpiresAt: time.Now().Add(time.Hour),
+			FamilyID:  uuid.New(),
+		},
+		{
+			ID:        uuid.New(),
+			Username:  user.Username,
+			UserAgent: "grpc-go/1.66",
+			ClientIp:  "127.0.0.2",
+			CreatedAt: time.Now(),
+			ExpiresAt: time.Now().Add(time.Hour),
+			FamilyID:  uuid.New(),
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.ListSessionsRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.ListSessionsResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.ListSessionsRequest{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(sessions, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListSessionsResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+				require.Len(t, res.GetSessions(), len(sessions))
+				for i, session := range res.GetSessions() {
+					require.Equal(t, sessions[i].ID.String(), session.GetId())
+					require.Equal(t, sessions[i].Username, session.GetUsername())
+					require.Equal(t, sessions[i].UserAgent, session.GetUserAgent())
+					require.Equal(t, sessions[i].ClientIp, session.GetClientIp())
+				}
+			},
+		},
+		{
+			name: "OwnUsername",
+			req:  &pb.ListSessionsRequest{Username: &user.Username},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(sessions, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListSessionsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetSessions(), len(sessions))
+			},
+		},
+		{
+			name: "BankerListsOtherUser",
+			req:  &pb.ListSessionsRequest{Username: &user.Username},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(sessions, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, other.Username, util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListSessionsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetSessions(), len(sessions))
+			},
+		},
+		{
+			name: "DepositorListsOtherUser",
+			req:  &pb.ListSessionsRequest{Username: &user.Username},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, other.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListSessionsResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidUsername",
+			req:  &pb.ListSessionsRequest{Username: &invalidUsername},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListSessionsResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			req:  &pb.ListSessionsRequest{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListSessionsResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "NoAuthorization",
+			req:  &pb.ListSessionsRequest{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, res *pb.ListSessionsResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubActiveSession(store)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.ListSessions(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
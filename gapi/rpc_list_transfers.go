@@ -0,0 +1,122 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListTransfers(ctx context.Context, req *pb.ListTransfersRequest) (*pb.ListTransfersResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []util.Role{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListTransfersRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	if authPayload.Role != util.BankerRole && account.Owner != authPayload.Username {
+		return nil, permissionDeniedError(errors.New("account doesn't belong to the authenticated user"))
+	}
+
+	// Fetch one extra row to find out whether there is a next page
+	arg := db.ListAccountTransfersParams{
+		AccountID: account.ID,
+		Direction: req.GetDirection(),
+		PageSize:  req.GetPageSize() + 1,
+	}
+	if req.CounterpartyAccountId != nil {
+		arg.CounterpartyID = sql.NullInt64{Int64: req.GetCounterpartyAccountId(), Valid: true}
+	}
+	if req.MinAmount != nil {
+		arg.MinAmount = sql.NullInt64{Int64: req.GetMinAmount(), Valid: true}
+	}
+	if req.MaxAmount != nil {
+		arg.MaxAmount = sql.NullInt64{Int64: req.GetMaxAmount(), Valid: true}
+	}
+	if req.FromTime != nil {
+		arg.FromTime = sql.NullTime{Time: req.GetFromTime().AsTime(), Valid: true}
+	}
+	if req.ToTime != nil {
+		arg.ToTime = sql.NullTime{Time: req.GetToTime().AsTime(), Valid: true}
+	}
+	if req.GetPageToken() != "" {
+		lastID, _ := decodePageToken(req.GetPageToken())
+		arg.BeforeID = sql.NullInt64{Int64: lastID, Valid: true}
+	}
+
+	transfers, err := server.store.ListAccountTransfers(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list transfers: %s", err)
+	}
+
+	resp := &pb.ListTransfersResponse{}
+	if len(transfers) > int(req.GetPageSize()) {
+		transfers = transfers[:req.GetPageSize()]
+		resp.NextPageToken = encodePageToken(transfers[len(transfers)-1].ID)
+	}
+
+	resp.Transfers = make([]*pb.Transfer, 0, len(transfers))
+	for _, transfer := range transfers {
+		resp.Transfers = append(resp.Transfers, convertAccountTransfer(server.currencies, transfer, account.Currency))
+	}
+
+	return resp, nil
+}
+
+func validateListTransfersRequest(req *pb.ListTransfersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := util.ValidateAccountId(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolations("account_id", err))
+	}
+	if req.Direction != nil {
+		if err := util.ValidateTransferDirection(req.GetDirection()); err != nil {
+			violations = append(violations, fieldViolations("direction", err))
+		}
+	}
+	if req.CounterpartyAccountId != nil {
+		if err := util.ValidateAccountId(req.GetCounterpartyAccountId()); err != nil {
+			violations = append(violations, fieldViolations("counterparty_account_id", err))
+		}
+	}
+	if req.MinAmount != nil {
+		if err := util.ValidateAmount(req.GetMinAmount()); err != nil {
+			violations = append(violations, fieldViolations("min_amount", err))
+		}
+	}
+	if req.MaxAmount != nil {
+		if err := util.ValidateAmount(req.GetMaxAmount()); err != nil {
+			violations = append(violations, fieldViolations("max_amount", err))
+		} else if req.MinAmount != nil && req.GetMaxAmount() < req.GetMinAmount() {
+			violations = append(violations, fieldViolations("max_amount", errors.New("must not be less than min_amount")))
+		}
+	}
+	if req.FromTime != nil && req.ToTime != nil && !req.GetFromTime().AsTime().Before(req.GetToTime().AsTime()) {
+		violations = append(violations, fieldViolations("to_time", errors.New("must be after from_time")))
+	}
+	if err := util.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolations("page_size", err))
+	}
+	if req.GetPageToken() != "" {
+		if _, err := decodePageToken(req.GetPageToken()); err != nil {
+			violations = append(violations, fieldViolations("page_token", err))
+		}
+	}
+	return violations
+}
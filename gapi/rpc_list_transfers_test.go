@@ -0,0 +1,219 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/token"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListTransfersAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+	banker, _ := randomUser(t)
+
+	account := randomAccount(user.Username)
+	counterparty := randomAccount(other.Username)
+
+	pageSize := int32(3)
+	transfers := make([]db.ListAccountTransfersRow, pageSize+1)
+	for i := range transfers {
+		amount := util.RandomMoney()
+		transfers[i] = db.ListAccountTransfersRow{
+			ID:                    int64(50 - i),
+			FromAccountID:         account.ID,
+			ToAccountID:           counterparty.ID,
+			Amount:                amount,
+			CreatedAt:             time.Now(),
+			CounterpartyAccountID: counterparty.ID,
+			CounterpartyCurrency:  counterparty.Currency,
+			CounterpartyAmount:    amount,
+		}
+	}
+
+	direction := util.OutgoingDirection
+	minAmount := int64(100)
+	maxAmount := int64(50)
+
+	testCases := []struct {
+		name          string
+		req           *pb.ListTransfersRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.ListTransfersResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.ListTransfersRequest{
+				AccountId:             account.ID,
+				Direction:             &direction,
+				CounterpartyAccountId: &counterparty.ID,
+				PageSize:              pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListAccountTransfersParams{
+					AccountID:      account.ID,
+					Direction:      direction,
+					CounterpartyID: sql.NullInt64{Int64: counterparty.ID, Valid: true},
+					PageSize:       pageSize + 1,
+				}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountTransfers(gomock.Any(), gomock.Eq(arg)).Times(1).Return(transfers, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetTransfers(), int(pageSize))
+				require.Equal(t, account.Currency, res.GetTransfers()[0].GetCurrency())
+				require.Equal(t, encodePageToken(transfers[pageSize-1].ID), res.GetNextPageToken())
+			},
+		},
+		{
+			name: "LastPage",
+			req: &pb.ListTransfersRequest{
+				AccountId: account.ID,
+				PageSize:  pageSize,
+				PageToken: encodePageToken(transfers[0].ID),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListAccountTransfersParams{
+					AccountID: account.ID,
+					BeforeID:  sql.NullInt64{Int64: transfers[0].ID, Valid: true},
+					PageSize:  pageSize + 1,
+				}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountTransfers(gomock.Any(), gomock.Eq(arg)).Times(1).Return(transfers[1:], nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetTransfers(), int(pageSize))
+				require.Empty(t, res.GetNextPageToken())
+			},
+		},
+		{
+			name: "ExchangeLeg",
+			req: &pb.ListTransfersRequest{
+				AccountId: account.ID,
+				PageSize:  pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				leg := db.ListAccountTransfersRow{
+					ID:                    60,
+					FromAccountID:         account.ID,
+					ToAccountID:           counterparty.ID + 1,
+					Amount:                100,
+					CreatedAt:             time.Now(),
+					CounterpartyAccountID: counterparty.ID,
+					CounterpartyCurrency:  util.EUR,
+					CounterpartyAmount:    92,
+					ExchangeRate:          sql.NullString{String: "0.92000000", Valid: true},
+				}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountTransfers(gomock.Any(), gomock.Any()).Times(1).Return([]db.ListAccountTransfersRow{leg}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetTransfers(), 1)
+
+				transfer := res.GetTransfers()[0]
+				require.Equal(t, account.Currency, transfer.GetCurrency())
+				require.Equal(t, counterparty.ID, transfer.GetCounterpartyAccountId())
+				require.Equal(t, util.EUR, transfer.GetCounterpartyCurrency())
+				require.Equal(t, int64(92), transfer.GetCounterpartyAmount())
+				require.Equal(t, util.NewDefaultCurrencies().FormatAmount(92, util.EUR), transfer.GetFormattedCounterpartyAmount())
+				require.Equal(t, "0.92000000", transfer.GetExchangeRate())
+			},
+		},
+		{
+			name: "BankerListsAnyAccount",
+			req: &pb.ListTransfersRequest{
+				AccountId: account.ID,
+				PageSize:  pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountTransfers(gomock.Any(), gomock.Any()).Times(1).Return([]db.ListAccountTransfersRow{}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.NoError(t, err)
+				require.Empty(t, res.GetTransfers())
+			},
+		},
+		{
+			name: "NotOwner",
+			req: &pb.ListTransfersRequest{
+				AccountId: account.ID,
+				PageSize:  pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, other.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidAmountRange",
+			req: &pb.ListTransfersRequest{
+				AccountId: account.ID,
+				MinAmount: &minAmount,
+				MaxAmount: &maxAmount,
+				PageSize:  pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubActiveSession(store)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.ListTransfers(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 
+	"github.com/google/uuid"
 	db "github.com/guncv/Simple-Bank/db/sqlc"
 	"github.com/guncv/Simple-Bank/pb"
 	"github.com/guncv/Simple-Bank/util"
@@ -33,12 +34,17 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 		return nil, status.Errorf(codes.NotFound, "incorrect password")
 	}
 
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, util.Role(user.Role), server.config.AccessTokenDuration)
+	sessionID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create session id: %v", err)
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, util.Role(user.Role), sessionID, server.config.AccessTokenDuration)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create access token: %v", err)
 	}
 
-	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, util.Role(user.Role), server.config.RefreshTokenDuration)
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, util.Role(user.Role), sessionID, server.config.RefreshTokenDuration)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create refresh token: %v", err)
 	}
@@ -49,13 +55,14 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 	}
 
 	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
-		ID:           refreshPayload.ID,
+		ID:           sessionID,
 		Username:     user.Username,
 		RefreshToken: refreshToken,
 		UserAgent:    metadata.UserAgent,
 		ClientIp:     metadata.ClientIp,
 		IsBlocked:    false,
 		ExpiresAt:    refreshPayload.ExpiredAt,
+		FamilyID:     sessionID,
 	})
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create session: %v", err)
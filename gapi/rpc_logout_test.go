@@ -0,0 +1,139 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLogoutAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore, session db.Sessions)
+		checkResponse func(t *testing.T, res *pb.LogoutResponse, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.LogoutResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+			},
+		},
+		{
+			name: "SessionNotFound",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(db.Sessions{}, sql.ErrNoRows)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LogoutResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "SessionOfAnotherUser",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				session.Username = other.Username
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LogoutResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "MismatchedRefreshToken",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				session.RefreshToken = util.RandomString(32)
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LogoutResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).Times(1).Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.LogoutResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store, nil)
+
+			refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, util.DepositorRole, uuid.New(), time.Minute)
+			require.NoError(t, err)
+
+			session := db.Sessions{
+				ID:           refreshPayload.SessionID,
+				Username:     user.Username,
+				RefreshToken: refreshToken,
+				ExpiresAt:    refreshPayload.ExpiredAt,
+				FamilyID:     uuid.New(),
+			}
+			tc.buildStubs(store, session)
+
+			req := &pb.LogoutRequest{RefreshToken: refreshToken}
+			res, err := server.Logout(context.Background(), req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestLogoutInvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+	server := newTestServer(t, store, nil)
+
+	res, err := server.Logout(context.Background(), &pb.LogoutRequest{})
+	require.Error(t, err)
+	require.Nil(t, res)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	res, err = server.Logout(context.Background(), &pb.LogoutRequest{RefreshToken: "invalid"})
+	require.Error(t, err)
+	require.Nil(t, res)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
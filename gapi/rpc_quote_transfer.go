@@ -0,0 +1,123 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/policy"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// quoteValidity is how long a quote can be executed after it was issued
+const quoteValidity = time.Minute
+
+func (server *Server) QuoteTransfer(ctx context.Context, req *pb.QuoteTransferRequest) (*pb.QuoteTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []util.Role{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateQuoteTransferRequest(req, server.currencies)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if err := server.enforcePolicy(ctx, authPayload.Username, policy.ActionTransfer); err != nil {
+		return nil, err
+	}
+
+	fromAccount, err := server.validAccount(ctx, req.GetFromAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	if fromAccount.Owner != authPayload.Username {
+		return nil, permissionDeniedError(errors.New("from account doesn't belong to the authenticated user"))
+	}
+
+	// the target account may hold another currency, in which case the quote is for an exchange transfer
+	toAccount, err := server.store.GetAccount(ctx, req.GetToAccountId())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "account [%d] not found", req.GetToAccountId())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	result, err := server.store.QuoteTransferTx(ctx, db.QuoteTransferTxParams{
+		Username:      authPayload.Username,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        req.GetAmount(),
+		ExpiresAt:     time.Now().Add(quoteValidity),
+		Currencies:    server.currencies,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		if errors.Is(err, db.ErrSystemAccount) {
+			return nil, permissionDeniedError(err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to quote transfer: %s", err)
+	}
+
+	resp := &pb.QuoteTransferResponse{
+		Quote: convertTransferQuote(server.currencies, req.GetAmount(), fromAccount, toAccount, result),
+	}
+
+	return resp, nil
+}
+
+func validateQuoteTransferRequest(req *pb.QuoteTransferRequest, currencies util.Currencies) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := util.ValidateAccountId(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolations("from_account_id", err))
+	}
+	if err := util.ValidateAccountId(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolations("to_account_id", err))
+	}
+	if req.GetFromAccountId() == req.GetToAccountId() {
+		violations = append(violations, fieldViolations("to_account_id", errors.New("must be different from from_account_id")))
+	}
+	if err := util.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, fieldViolations("amount", err))
+	}
+	if err := currencies.Validate(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolations("currency", err))
+	}
+	return violations
+}
+
+func convertTransferQuote(currencies util.Currencies, amount int64, fromAccount db.Accounts, toAccount db.Accounts, result db.QuoteTransferTxResult) *pb.TransferQuote {
+	quote := &pb.TransferQuote{
+		FromAccountId: fromAccount.ID,
+		ToAccountId:   toAccount.ID,
+		Amount:        amount,
+		Currency:      fromAccount.Currency,
+		ToCurrency:    toAccount.Currency,
+		Executable:    result.Rejection == nil,
+	}
+	if result.Rejection != nil {
+		quote.RejectionReason = result.Rejection.Error()
+		return quote
+	}
+
+	quote.Id = result.Quote.ID.String()
+	quote.Fee = result.Fee
+	quote.FormattedFee = currencies.FormatAmount(result.Fee, fromAccount.Currency)
+	quote.ResultingBalance = result.FromAccount.Balance
+	quote.FormattedResultingBalance = currencies.FormatAmount(result.FromAccount.Balance, fromAccount.Currency)
+	quote.ConvertedAmount = result.ConvertedAmount
+	quote.FormattedConvertedAmount = currencies.FormatAmount(result.ConvertedAmount, toAccount.Currency)
+	quote.Rate = result.Rate
+	quote.ExpiresAt = timestamppb.New(result.Quote.ExpiresAt)
+	return quote
+}
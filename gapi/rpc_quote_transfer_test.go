@@ -0,0 +1,188 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/token"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestQuoteTransferAPI(t *testing.T) {
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+
+	fromAccount := randomAccount(user1.Username)
+	fromAccount.ID = 1
+	fromAccount.Balance = 1000
+	fromAccount.Currency = util.USD
+	toAccount := randomAccount(user2.Username)
+	toAccount.ID = 2
+	toAccount.Currency = util.EUR
+
+	req := &pb.QuoteTransferRequest{
+		FromAccountId: fromAccount.ID,
+		ToAccountId:   toAccount.ID,
+		Amount:        100,
+		Currency:      util.USD,
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.QuoteTransferRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.QuoteTransferResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  req,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+
+				resultingAccount := fromAccount
+				resultingAccount.Balance = 900
+				result := db.QuoteTransferTxResult{
+					Quote: db.TransferQuotes{
+						ID:        uuid.New(),
+						ExpiresAt: time.Now().Add(quoteValidity),
+					},
+					FromAccount:     resultingAccount,
+					ToAccount:       toAccount,
+					Rate:            "0.92000000",
+					ConvertedAmount: 92,
+				}
+				store.EXPECT().
+					QuoteTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.QuoteTransferTxParams) (db.QuoteTransferTxResult, error) {
+						require.Equal(t, user1.Username, arg.Username)
+						require.Equal(t, int64(100), arg.Amount)
+						require.WithinDuration(t, time.Now().Add(quoteValidity), arg.ExpiresAt, time.Second)
+						return result, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.QuoteTransferResponse, err error) {
+				require.NoError(t, err)
+				quote := res.GetQuote()
+				require.True(t, quote.GetExecutable())
+				require.NotEmpty(t, quote.GetId())
+				require.Equal(t, int64(900), quote.GetResultingBalance())
+				require.Equal(t, "$9.00", quote.GetFormattedResultingBalance())
+				require.Equal(t, "0.92000000", quote.GetRate())
+				require.Equal(t, util.EUR, quote.GetToCurrency())
+				require.Equal(t, int64(92), quote.GetConvertedAmount())
+			},
+		},
+		{
+			name: "Rejected",
+			req:  req,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				result := db.QuoteTransferTxResult{
+					Rejection: fmt.Errorf("%w: EUR", db.ErrExchangeRateNotFound),
+				}
+				store.EXPECT().QuoteTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(result, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.QuoteTransferResponse, err error) {
+				require.NoError(t, err)
+				quote := res.GetQuote()
+				require.False(t, quote.GetExecutable())
+				require.Empty(t, quote.GetId())
+				require.Contains(t, quote.GetRejectionReason(), db.ErrExchangeRateNotFound.Error())
+			},
+		},
+		{
+			name: "NotOwner",
+			req:  req,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().QuoteTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user2.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.QuoteTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "CurrencyMismatch",
+			req: &pb.QuoteTransferRequest{
+				FromAccountId: fromAccount.ID,
+				ToAccountId:   toAccount.ID,
+				Amount:        100,
+				Currency:      util.THB,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().QuoteTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.QuoteTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidAmount",
+			req: &pb.QuoteTransferRequest{
+				FromAccountId: fromAccount.ID,
+				ToAccountId:   toAccount.ID,
+				Amount:        -1,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().QuoteTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.QuoteTransferResponse, err error) {
+				require.Error(t, err)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubActiveSession(store)
+			stubVerifiedUser(store)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.QuoteTransfer(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/token"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (server *Server) RenewAccessToken(ctx context.Context, req *pb.RenewAccessTokenRequest) (*pb.RenewAccessTokenResponse, error) {
+	violations := validateRenewAccessTokenRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	refreshPayload, err := server.tokenMaker.VerifyToken(req.GetRefreshToken())
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	session, err := server.store.GetSession(ctx, refreshPayload.SessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "session not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get session: %s", err)
+	}
+
+	if session.Username != refreshPayload.Username {
+		return nil, unauthenticatedError(errors.New("incorrect session user"))
+	}
+
+	if session.RefreshToken != req.GetRefreshToken() {
+		return nil, unauthenticatedError(errors.New("mismatched session token"))
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		return nil, unauthenticatedError(errors.New("session expired"))
+	}
+
+	// every rotation starts a new session in the same family
+	newSessionID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create session id: %v", err)
+	}
+
+	metadata, err := server.extractMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var accessToken, refreshToken string
+	var accessPayload, newRefreshPayload *token.Payload
+	result, err := server.store.RotateSessionTx(ctx, db.RotateSessionTxParams{
+		SessionID: session.ID,
+		IssuedAt:  refreshPayload.IssuedAt,
+		NewSession: func(user db.Users) (db.CreateSessionParams, error) {
+			// the role comes from the user so that a role change applies on the next renewal
+			var err error
+			accessToken, accessPayload, err = server.tokenMaker.CreateToken(user.Username, util.Role(user.Role), newSessionID, server.config.AccessTokenDuration)
+			if err != nil {
+				return db.CreateSessionParams{}, fmt.Errorf("failed to create access token: %w", err)
+			}
+
+			refreshToken, newRefreshPayload, err = server.tokenMaker.CreateToken(user.Username, util.Role(user.Role), newSessionID, server.config.RefreshTokenDuration)
+			if err != nil {
+				return db.CreateSessionParams{}, fmt.Errorf("failed to create refresh token: %w", err)
+			}
+
+			return db.CreateSessionParams{
+				ID:           newSessionID,
+				Username:     user.Username,
+				RefreshToken: refreshToken,
+				UserAgent:    metadata.UserAgent,
+				ClientIp:     metadata.ClientIp,
+				IsBlocked:    false,
+				ExpiresAt:    newRefreshPayload.ExpiredAt,
+			}, nil
+		},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRefreshTokenReused) {
+			server.sessionCache.invalidateFamily(session.FamilyID)
+			return nil, unauthenticatedError(errors.New("refresh token reuse detected, all sessions of this login have been blocked"))
+		}
+		if errors.Is(err, db.ErrSessionBlocked) || errors.Is(err, db.ErrPasswordChanged) {
+			return nil, unauthenticatedError(err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to rotate session: %v", err)
+	}
+
+	rsp := &pb.RenewAccessTokenResponse{
+		SessionId:             result.NewSession.ID.String(),
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  timestamppb.New(accessPayload.ExpiredAt),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(newRefreshPayload.ExpiredAt),
+	}
+
+	return rsp, nil
+}
+
+func validateRenewAccessTokenRequest(req *pb.RenewAccessTokenRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetRefreshToken() == "" {
+		violations = append(violations, fieldViolations("refresh_token", errors.New("must not be empty")))
+	}
+
+	return violations
+}
@@ -0,0 +1,212 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rotateSession plays the part of RotateSessionTx for a successful rotation
+func rotateSession(t *testing.T, session db.Sessions, user db.Users, arg db.RotateSessionTxParams) (db.RotateSessionTxResult, error) {
+	require.Equal(t, session.ID, arg.SessionID)
+	require.False(t, arg.IssuedAt.IsZero())
+
+	newSession, err := arg.NewSession(user)
+	if err != nil {
+		return db.RotateSessionTxResult{}, err
+	}
+	require.NotEqual(t, session.ID, newSession.ID)
+	require.Equal(t, session.Username, newSession.Username)
+	require.NotEqual(t, session.RefreshToken, newSession.RefreshToken)
+
+	return db.RotateSessionTxResult{
+		OldSession: session,
+		NewSession: db.Sessions{
+			ID:           newSession.ID,
+			Username:     newSession.Username,
+			RefreshToken: newSession.RefreshToken,
+			ExpiresAt:    newSession.ExpiresAt,
+			FamilyID:     session.FamilyID,
+		},
+		User: user,
+	}, nil
+}
+
+func TestRenewAccessTokenAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore, session db.Sessions)
+		checkResponse func(t *testing.T, res *pb.RenewAccessTokenResponse, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.RotateSessionTxParams) (db.RotateSessionTxResult, error) {
+						return rotateSession(t, session, user, arg)
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+				require.NotEmpty(t, res.GetAccessToken())
+				require.NotEmpty(t, res.GetRefreshToken())
+				require.NotEmpty(t, res.GetSessionId())
+			},
+		},
+		{
+			name: "SessionNotFound",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(db.Sessions{}, sql.ErrNoRows)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "MismatchedRefreshToken",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				session.RefreshToken = util.RandomString(32)
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "ExpiredSession",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				session.ExpiresAt = time.Now().Add(-time.Minute)
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "RefreshTokenReused",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(1).Return(db.RotateSessionTxResult{}, db.ErrRefreshTokenReused)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "PasswordChanged",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(1).Return(db.RotateSessionTxResult{}, db.ErrPasswordChanged)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "BlockedSession",
+			buildStubs: func(store *mockdb.MockStore, session db.Sessions) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(1).Return(db.RotateSessionTxResult{}, db.ErrSessionBlocked)
+			},
+			checkResponse: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store, nil)
+
+			refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, util.DepositorRole, uuid.New(), time.Minute)
+			require.NoError(t, err)
+
+			session := db.Sessions{
+				ID:           refreshPayload.SessionID,
+				Username:     user.Username,
+				RefreshToken: refreshToken,
+				ExpiresAt:    refreshPayload.ExpiredAt,
+				FamilyID:     refreshPayload.SessionID,
+			}
+			tc.buildStubs(store, session)
+
+			req := &pb.RenewAccessTokenRequest{RefreshToken: refreshToken}
+			res, err := server.RenewAccessToken(context.Background(), req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestRenewAccessTokenRoleChanged(t *testing.T) {
+	user, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store, nil)
+
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, util.DepositorRole, uuid.New(), time.Minute)
+	require.NoError(t, err)
+
+	session := db.Sessions{
+		ID:           refreshPayload.SessionID,
+		Username:     user.Username,
+		RefreshToken: refreshToken,
+		ExpiresAt:    refreshPayload.ExpiredAt,
+		FamilyID:     refreshPayload.SessionID,
+	}
+
+	// the user was promoted after the refresh token was issued
+	promoted := user
+	promoted.Role = string(util.BankerRole)
+
+	store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+	store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ any, arg db.RotateSessionTxParams) (db.RotateSessionTxResult, error) {
+			return rotateSession(t, session, promoted, arg)
+		})
+
+	res, err := server.RenewAccessToken(context.Background(), &pb.RenewAccessTokenRequest{RefreshToken: refreshToken})
+	require.NoError(t, err)
+
+	accessPayload, err := server.tokenMaker.VerifyToken(res.GetAccessToken())
+	require.NoError(t, err)
+	require.Equal(t, util.BankerRole, accessPayload.Role)
+}
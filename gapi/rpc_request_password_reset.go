@@ -0,0 +1,42 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/guncv/Simple-Bank/worker"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	violations := validateRequestPasswordResetRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// the email is looked up by the worker, so a known and an unknown email cost the same here
+	// and cannot be told apart by the response or its timing
+	message, err := worker.NewOutboxMessage(worker.TaskRequestPasswordReset, &worker.PayloadRequestPasswordReset{
+		Email: req.GetEmail(),
+	}, worker.QueueCritical, 10)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to build password reset task: %s", err)
+	}
+
+	_, err = server.store.CreateOutboxMessage(ctx, message)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to request password reset: %s", err)
+	}
+
+	return &pb.RequestPasswordResetResponse{}, nil
+}
+
+func validateRequestPasswordResetRequest(req *pb.RequestPasswordResetRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := util.ValidateEmail(req.GetEmail()); err != nil {
+		violations = append(violations, fieldViolations("email", err))
+	}
+	return violations
+}
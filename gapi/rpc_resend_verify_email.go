@@ -0,0 +1,73 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/guncv/Simple-Bank/worker"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// verifyEmailResendInterval is the minimum time between two verification emails of a user
+const verifyEmailResendInterval = time.Minute
+
+func (server *Server) ResendVerifyEmail(ctx context.Context, req *pb.ResendVerifyEmailRequest) (*pb.ResendVerifyEmailResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []util.Role{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateResendVerifyEmailRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	username := authPayload.Username
+	if req.Username != nil {
+		if authPayload.Role != util.BankerRole && req.GetUsername() != authPayload.Username {
+			return nil, permissionDeniedError(errors.New("cannot resend verify email of another user"))
+		}
+		username = req.GetUsername()
+	}
+
+	_, err = server.store.ResendVerifyEmailTx(ctx, db.ResendVerifyEmailTxParams{
+		Username: username,
+		Cooldown: verifyEmailResendInterval,
+		NewMessage: func(user db.Users) (db.CreateOutboxMessageParams, error) {
+			taskPayload := &worker.PayloadSendVerifyEmail{
+				Username: user.Username,
+			}
+			return worker.NewOutboxMessage(worker.TaskSendVerifyEmail, taskPayload, worker.QueueCritical, 10)
+		},
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		if errors.Is(err, db.ErrEmailAlreadyVerified) {
+			return nil, status.Errorf(codes.FailedPrecondition, "email is already verified")
+		}
+		if errors.Is(err, db.ErrVerifyEmailTooSoon) {
+			return nil, status.Errorf(codes.ResourceExhausted, "verify email was sent recently, try again later")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to resend verify email: %s", err)
+	}
+
+	return &pb.ResendVerifyEmailResponse{}, nil
+}
+
+func validateResendVerifyEmailRequest(req *pb.ResendVerifyEmailRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.Username != nil {
+		if err := util.ValidateUsername(req.GetUsername()); err != nil {
+			violations = append(violations, fieldViolations("username", err))
+		}
+	}
+	return violations
+}
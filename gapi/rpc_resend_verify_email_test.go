@@ -0,0 +1,166 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/token"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/guncv/Simple-Bank/worker"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestResendVerifyEmailAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	// checkResendArg runs the transaction callback and checks the task written to the outbox
+	checkResendArg := func(t *testing.T, arg db.ResendVerifyEmailTxParams) {
+		require.Equal(t, user.Username, arg.Username)
+		require.Positive(t, arg.Cooldown)
+
+		message, err := arg.NewMessage(user)
+		require.NoError(t, err)
+		require.Equal(t, worker.TaskSendVerifyEmail, message.TaskType)
+		require.Equal(t, worker.QueueCritical, message.Queue)
+
+		var payload worker.PayloadSendVerifyEmail
+		require.NoError(t, json.Unmarshal(message.Payload, &payload))
+		require.Equal(t, user.Username, payload.Username)
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.ResendVerifyEmailRequest
+		buildStubs    func(store *mockdb.MockStore)
+		buildContext  func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponse func(t *testing.T, res *pb.ResendVerifyEmailResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.ResendVerifyEmailRequest{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ResendVerifyEmailTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.ResendVerifyEmailTxParams) (db.ResendVerifyEmailTxResult, error) {
+						checkResendArg(t, arg)
+						return db.ResendVerifyEmailTxResult{User: user}, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResendVerifyEmailResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+			},
+		},
+		{
+			name: "BankerResendsForOtherUser",
+			req:  &pb.ResendVerifyEmailRequest{Username: &user.Username},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ResendVerifyEmailTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.ResendVerifyEmailTxParams) (db.ResendVerifyEmailTxResult, error) {
+						checkResendArg(t, arg)
+						return db.ResendVerifyEmailTxResult{User: user}, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, other.Username, util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResendVerifyEmailResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+			},
+		},
+		{
+			name: "DepositorResendsForOtherUser",
+			req:  &pb.ResendVerifyEmailRequest{Username: &user.Username},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ResendVerifyEmailTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, other.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResendVerifyEmailResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "UserNotFound",
+			req:  &pb.ResendVerifyEmailRequest{Username: &user.Username},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ResendVerifyEmailTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.ResendVerifyEmailTxResult{}, sql.ErrNoRows)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, other.Username, util.BankerRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResendVerifyEmailResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "AlreadyVerified",
+			req:  &pb.ResendVerifyEmailRequest{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ResendVerifyEmailTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.ResendVerifyEmailTxResult{}, db.ErrEmailAlreadyVerified)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResendVerifyEmailResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "SentRecently",
+			req:  &pb.ResendVerifyEmailRequest{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ResendVerifyEmailTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.ResendVerifyEmailTxResult{}, db.ErrVerifyEmailTooSoon)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResendVerifyEmailResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.ResourceExhausted, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubActiveSession(store)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := tc.buildContext(t, server.tokenMaker)
+			res, err := server.ResendVerifyEmail(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error) {
+	violations := validateResetPasswordRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	hashedPassword, err := util.HashPassword(req.GetNewPassword())
+	if err != nil {
+		log.Printf("failed to hash password: %s", err)
+		return nil, status.Errorf(codes.Internal, "failed to hash password: %s", err)
+	}
+
+	arg := db.ResetPasswordTxParams{
+		ResetID:        req.GetResetId(),
+		SecretCode:     req.GetSecretCode(),
+		HashedPassword: hashedPassword,
+	}
+
+	result, err := server.store.ResetPasswordTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "password reset not found, used or expired")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to reset password: %s", err)
+	}
+	server.sessionCache.invalidateUser(result.User.Username)
+
+	return &pb.ResetPasswordResponse{}, nil
+}
+
+func validateResetPasswordRequest(req *pb.ResetPasswordRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := util.ValidateResetId(req.GetResetId()); err != nil {
+		violations = append(violations, fieldViolations("reset_id", err))
+	}
+	if err := util.ValidateSecretCode(req.GetSecretCode()); err != nil {
+		violations = append(violations, fieldViolations("secret_code", err))
+	}
+	if err := util.ValidatePassword(req.GetNewPassword()); err != nil {
+		violations = append(violations, fieldViolations("new_password", err))
+	}
+	return violations
+}
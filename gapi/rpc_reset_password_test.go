@@ -0,0 +1,260 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/guncv/Simple-Bank/worker"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type eqResetPasswordTxParamsMatcher struct {
+	arg      db.ResetPasswordTxParams
+	password string
+}
+
+func (expected eqResetPasswordTxParamsMatcher) Matches(x interface{}) bool {
+	actualArg, ok := x.(db.ResetPasswordTxParams)
+	if !ok {
+		return false
+	}
+
+	if err := util.CheckPassword(expected.password, actualArg.HashedPassword); err != nil {
+		return false
+	}
+
+	return expected.arg.ResetID == actualArg.ResetID && expected.arg.SecretCode == actualArg.SecretCode
+}
+
+func (expected eqResetPasswordTxParamsMatcher) String() string {
+	return fmt.Sprintf("matches arg %v and password %v", expected.arg, expected.password)
+}
+
+func EqResetPasswordTxParams(arg db.ResetPasswordTxParams, password string) gomock.Matcher {
+	return eqResetPasswordTxParamsMatcher{arg, password}
+}
+
+func TestResetPasswordAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	newPassword := util.RandomString(8)
+	secretCode := util.RandomString(32)
+
+	testCases := []struct {
+		name          string
+		req           *pb.ResetPasswordRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.ResetPasswordResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.ResetPasswordRequest{
+				ResetId:     1,
+				SecretCode:  secretCode,
+				NewPassword: newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ResetPasswordTxParams{
+					ResetID:    1,
+					SecretCode: secretCode,
+				}
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), EqResetPasswordTxParams(arg, newPassword)).
+					Times(1).
+					Return(db.ResetPasswordTxResult{User: user}, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+			},
+		},
+		{
+			name: "NotFound",
+			req: &pb.ResetPasswordRequest{
+				ResetId:     1,
+				SecretCode:  secretCode,
+				NewPassword: newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ResetPasswordTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "InternalError",
+			req: &pb.ResetPasswordRequest{
+				ResetId:     1,
+				SecretCode:  secretCode,
+				NewPassword: newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ResetPasswordTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidSecretCode",
+			req: &pb.ResetPasswordRequest{
+				ResetId:     1,
+				SecretCode:  "short",
+				NewPassword: newPassword,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidPassword",
+			req: &pb.ResetPasswordRequest{
+				ResetId:     1,
+				SecretCode:  secretCode,
+				NewPassword: "123",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ResetPasswordResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			res, err := server.ResetPassword(context.Background(), tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestRequestPasswordResetAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	unknownEmail := util.RandomEmail()
+
+	// every valid email is queued the same way, whether or not it belongs to a user
+	expectQueued := func(email string) func(store *mockdb.MockStore) {
+		return func(store *mockdb.MockStore) {
+			store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(0)
+			store.EXPECT().RequestPasswordResetTx(gomock.Any(), gomock.Any()).Times(0)
+			store.EXPECT().CreateOutboxMessage(gomock.Any(), gomock.Any()).Times(1).
+				DoAndReturn(func(_ any, arg db.CreateOutboxMessageParams) (db.Outbox, error) {
+					require.Equal(t, worker.TaskRequestPasswordReset, arg.TaskType)
+					require.Equal(t, worker.QueueCritical, arg.Queue)
+
+					var payload worker.PayloadRequestPasswordReset
+					require.NoError(t, json.Unmarshal(arg.Payload, &payload))
+					require.Equal(t, worker.PayloadRequestPasswordReset{Email: email}, payload)
+					return db.Outbox{ID: 1, TaskType: arg.TaskType, Payload: arg.Payload, Queue: arg.Queue, MaxRetry: arg.MaxRetry}, nil
+				})
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.RequestPasswordResetRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.RequestPasswordResetResponse, err error)
+	}{
+		{
+			name:       "OK",
+			req:        &pb.RequestPasswordResetRequest{Email: user.Email},
+			buildStubs: expectQueued(user.Email),
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+			},
+		},
+		{
+			name:       "UnknownEmail",
+			req:        &pb.RequestPasswordResetRequest{Email: unknownEmail},
+			buildStubs: expectQueued(unknownEmail),
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+			},
+		},
+		{
+			name: "InternalError",
+			req:  &pb.RequestPasswordResetRequest{Email: user.Email},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateOutboxMessage(gomock.Any(), gomock.Any()).Times(1).Return(db.Outbox{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			name: "InvalidEmail",
+			req:  &pb.RequestPasswordResetRequest{Email: "invalid-email"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateOutboxMessage(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.RequestPasswordResetResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			res, err := server.RequestPasswordReset(context.Background(), tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ReverseTransfer(ctx context.Context, req *pb.ReverseTransferRequest) (*pb.ReverseTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []util.Role{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateReverseTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	result, err := server.store.ReverseTransferTx(ctx, db.ReverseTransferTxParams{
+		TransferID:  req.GetTransferId(),
+		Amount:      req.GetAmount(),
+		InitiatedBy: authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "transfer [%d] not found", req.GetTransferId())
+		}
+		if errors.Is(err, db.ErrTransferAlreadyReversed) {
+			return nil, status.Errorf(codes.AlreadyExists, "%s", err)
+		}
+		if errors.Is(err, db.ErrTransferNotReversible) ||
+			errors.Is(err, db.ErrReversalTooLarge) ||
+			errors.Is(err, db.ErrInsufficientFunds) ||
+			errors.Is(err, db.ErrAccountNotActive) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to reverse transfer: %s", err)
+	}
+
+	currency := result.FromAccount.Currency
+	resp := &pb.ReverseTransferResponse{
+		Reversal:        convertTransfer(server.currencies, result.Reversal, currency),
+		Original:        convertTransfer(server.currencies, result.Original, currency),
+		FromAccount:     convertAccount(server.currencies, result.FromAccount),
+		ToAccount:       convertAccount(server.currencies, result.ToAccount),
+		FromEntry:       convertEntry(server.currencies, result.FromEntry, currency),
+		ToEntry:         convertEntry(server.currencies, result.ToEntry, currency),
+		RemainingAmount: result.RemainingAmount,
+	}
+
+	return resp, nil
+}
+
+func validateReverseTransferRequest(req *pb.ReverseTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := util.ValidateTransferId(req.GetTransferId()); err != nil {
+		violations = append(violations, fieldViolations("transfer_id", err))
+	}
+	// omitting the amount refunds whatever is left of the transfer
+	if req.Amount != nil {
+		if err := util.ValidateAmount(req.GetAmount()); err != nil {
+			violations = append(violations, fieldViolations("amount", err))
+		}
+	}
+	return violations
+}
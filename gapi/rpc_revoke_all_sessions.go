@@ -0,0 +1,49 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) RevokeAllSessions(ctx context.Context, req *pb.RevokeAllSessionsRequest) (*pb.RevokeAllSessionsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []util.Role{util.BankerRole, util.DepositorRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateRevokeAllSessionsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	username := authPayload.Username
+	if req.Username != nil {
+		if authPayload.Role != util.BankerRole && req.GetUsername() != authPayload.Username {
+			return nil, permissionDeniedError(errors.New("cannot revoke sessions of another user"))
+		}
+		username = req.GetUsername()
+	}
+
+	err = server.store.BlockUserSessions(ctx, username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke sessions: %s", err)
+	}
+	server.sessionCache.invalidateUser(username)
+
+	return &pb.RevokeAllSessionsResponse{}, nil
+}
+
+func validateRevokeAllSessionsRequest(req *pb.RevokeAllSessionsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.Username != nil {
+		if err := util.ValidateUsername(req.GetUsername()); err != nil {
+			violations = append(violations, fieldViolations("username", err))
+		}
+	}
+	return violations
+}
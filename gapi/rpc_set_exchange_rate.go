@@ -0,0 +1,64 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) SetExchangeRate(ctx context.Context, req *pb.SetExchangeRateRequest) (*pb.SetExchangeRateResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []util.Role{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateSetExchangeRateRequest(req, server.currencies)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	effectiveAt := time.Now()
+	if req.EffectiveAt != nil {
+		effectiveAt = req.GetEffectiveAt().AsTime()
+	}
+
+	rate, err := server.store.CreateExchangeRate(ctx, db.CreateExchangeRateParams{
+		FromCurrency: req.GetFromCurrency(),
+		ToCurrency:   req.GetToCurrency(),
+		Rate:         req.GetRate(),
+		EffectiveAt:  effectiveAt,
+		CreatedBy:    authPayload.Username,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to set exchange rate: %s", err)
+	}
+
+	resp := &pb.SetExchangeRateResponse{
+		ExchangeRate: convertExchangeRate(rate),
+	}
+
+	return resp, nil
+}
+
+func validateSetExchangeRateRequest(req *pb.SetExchangeRateRequest, currencies util.Currencies) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := currencies.Validate(req.GetFromCurrency()); err != nil {
+		violations = append(violations, fieldViolations("from_currency", err))
+	}
+	if err := currencies.Validate(req.GetToCurrency()); err != nil {
+		violations = append(violations, fieldViolations("to_currency", err))
+	}
+	if req.GetFromCurrency() == req.GetToCurrency() {
+		violations = append(violations, fieldViolations("to_currency", errors.New("must differ from from_currency")))
+	}
+	if err := util.ValidateExchangeRate(req.GetRate()); err != nil {
+		violations = append(violations, fieldViolations("rate", err))
+	}
+	return violations
+}
@@ -0,0 +1,71 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) SetFeeSchedule(ctx context.Context, req *pb.SetFeeScheduleRequest) (*pb.SetFeeScheduleResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []util.Role{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateSetFeeScheduleRequest(req, server.currencies)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	schedule, err := server.store.UpsertFeeSchedule(ctx, db.UpsertFeeScheduleParams{
+		Currency:      req.GetCurrency(),
+		Role:          req.GetRole(),
+		FlatFee:       req.GetFlatFee(),
+		PercentageBps: req.GetPercentageBps(),
+		MinFee:        req.GetMinFee(),
+		MaxFee: sql.NullInt64{
+			Int64: req.GetMaxFee(),
+			Valid: req.MaxFee != nil,
+		},
+		UpdatedBy: authPayload.Username,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to set fee schedule: %s", err)
+	}
+
+	resp := &pb.SetFeeScheduleResponse{
+		FeeSchedule: convertFeeSchedule(schedule),
+	}
+
+	return resp, nil
+}
+
+func validateSetFeeScheduleRequest(req *pb.SetFeeScheduleRequest, currencies util.Currencies) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := currencies.Validate(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolations("currency", err))
+	}
+	if role := util.Role(req.GetRole()); role != util.DepositorRole && role != util.BankerRole {
+		violations = append(violations, fieldViolations("role", fmt.Errorf("must be %s or %s", util.DepositorRole, util.BankerRole)))
+	}
+	if req.GetFlatFee() < 0 {
+		violations = append(violations, fieldViolations("flat_fee", errors.New("must not be negative")))
+	}
+	if req.GetPercentageBps() < 0 || req.GetPercentageBps() > 10000 {
+		violations = append(violations, fieldViolations("percentage_bps", errors.New("must be between 0 and 10000")))
+	}
+	if req.GetMinFee() < 0 {
+		violations = append(violations, fieldViolations("min_fee", errors.New("must not be negative")))
+	}
+	if req.MaxFee != nil && req.GetMaxFee() < req.GetMinFee() {
+		violations = append(violations, fieldViolations("max_fee", errors.New("must not be less than min_fee")))
+	}
+	return violations
+}
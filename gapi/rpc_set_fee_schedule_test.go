@@ -0,0 +1,138 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSetFeeScheduleAPI(t *testing.T) {
+	banker, _ := randomUser(t)
+	user, _ := randomUser(t)
+	maxFee := int64(500)
+
+	testCases := []struct {
+		name         string
+		req          *pb.SetFeeScheduleRequest
+		role         util.Role
+		username     string
+		buildStubs   func(store *mockdb.MockStore)
+		expectedCode codes.Code
+	}{
+		{
+			name: "OK",
+			req: &pb.SetFeeScheduleRequest{
+				Currency:      util.USD,
+				Role:          string(util.DepositorRole),
+				FlatFee:       25,
+				PercentageBps: 50,
+				MaxFee:        &maxFee,
+			},
+			role:     util.BankerRole,
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertFeeSchedule(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpsertFeeScheduleParams) (db.FeeSchedules, error) {
+						require.Equal(t, banker.Username, arg.UpdatedBy)
+						require.True(t, arg.MaxFee.Valid)
+						require.Equal(t, maxFee, arg.MaxFee.Int64)
+						return db.FeeSchedules{
+							Currency:      arg.Currency,
+							Role:          arg.Role,
+							FlatFee:       arg.FlatFee,
+							PercentageBps: arg.PercentageBps,
+							MaxFee:        arg.MaxFee,
+							UpdatedBy:     arg.UpdatedBy,
+						}, nil
+					})
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "DepositorCannotSetFees",
+			req: &pb.SetFeeScheduleRequest{
+				Currency: util.USD,
+				Role:     string(util.DepositorRole),
+				FlatFee:  25,
+			},
+			role:     util.DepositorRole,
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "InvalidRole",
+			req: &pb.SetFeeScheduleRequest{
+				Currency: util.USD,
+				Role:     string(util.SystemRole),
+				FlatFee:  25,
+			},
+			role:     util.BankerRole,
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "MaxFeeBelowMinFee",
+			req: &pb.SetFeeScheduleRequest{
+				Currency: util.USD,
+				Role:     string(util.DepositorRole),
+				MinFee:   1000,
+				MaxFee:   &maxFee,
+			},
+			role:     util.BankerRole,
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "InvalidPercentage",
+			req: &pb.SetFeeScheduleRequest{
+				Currency:      util.USD,
+				Role:          string(util.DepositorRole),
+				PercentageBps: 10001,
+			},
+			role:     util.BankerRole,
+			username: banker.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubActiveSession(store)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+			ctx := newContextWithBearerToken(t, server.tokenMaker, tc.username, tc.role, time.Minute)
+			_, err := server.SetFeeSchedule(ctx, tc.req)
+			require.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) SetOverdraftLimit(ctx context.Context, req *pb.SetOverdraftLimitRequest) (*pb.SetOverdraftLimitResponse, error) {
+	_, err := server.authorizeUser(ctx, []util.Role{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateSetOverdraftLimitRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "account not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	if err := server.checkCustomerAccount(ctx, account); err != nil {
+		return nil, err
+	}
+
+	if account.Status == db.AccountStatusClosed {
+		return nil, status.Errorf(codes.FailedPrecondition, "account [%d] is %s", account.ID, account.Status)
+	}
+
+	if account.Balance < -req.GetOverdraftLimit() {
+		return nil, status.Errorf(codes.FailedPrecondition, "account [%d] balance is below the new overdraft limit", account.ID)
+	}
+
+	account, err = server.store.UpdateAccountOverdraftLimit(ctx, db.UpdateAccountOverdraftLimitParams{
+		OverdraftLimit: req.GetOverdraftLimit(),
+		ID:             req.GetId(),
+	})
+	if err != nil {
+		// the balance or status changed between the read and the update
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.FailedPrecondition, "account [%d] can no longer take the new overdraft limit", req.GetId())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to set overdraft limit: %s", err)
+	}
+
+	resp := &pb.SetOverdraftLimitResponse{
+		Account: convertAccount(server.currencies, account),
+	}
+
+	return resp, nil
+}
+
+func validateSetOverdraftLimitRequest(req *pb.SetOverdraftLimitRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := util.ValidateAccountId(req.GetId()); err != nil {
+		violations = append(violations, fieldViolations("id", err))
+	}
+	if req.GetOverdraftLimit() < 0 {
+		violations = append(violations, fieldViolations("overdraft_limit", errors.New("must not be negative")))
+	}
+	return violations
+}
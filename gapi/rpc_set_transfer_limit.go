@@ -0,0 +1,140 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/policy"
+	"github.com/guncv/Simple-Bank/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) SetTransferLimit(ctx context.Context, req *pb.SetTransferLimitRequest) (*pb.SetTransferLimitResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []util.Role{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateSetTransferLimitRequest(req, server.currencies)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	perTransaction := optionalLimit(req.PerTransaction)
+	daily := optionalLimit(req.Daily)
+	monthly := optionalLimit(req.Monthly)
+
+	// without a username the limit applies to every user of the role
+	if req.Username == nil {
+		_, err := server.store.UpsertTransferLimit(ctx, db.UpsertTransferLimitParams{
+			Role:           req.GetRole(),
+			Currency:       req.GetCurrency(),
+			PerTransaction: perTransaction,
+			Daily:          daily,
+			Monthly:        monthly,
+			UpdatedBy:      authPayload.Username,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to set transfer limit: %s", err)
+		}
+
+		resp := &pb.SetTransferLimitResponse{
+			Limit: &pb.TransferLimit{
+				Currency:       req.GetCurrency(),
+				PerTransaction: req.PerTransaction,
+				Daily:          req.Daily,
+				Monthly:        req.Monthly,
+			},
+		}
+		return resp, nil
+	}
+
+	user, err := server.store.GetUser(ctx, req.GetUsername())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get user: %s", err)
+	}
+
+	current, err := server.store.GetEffectiveTransferLimit(ctx, db.GetEffectiveTransferLimitParams{
+		Username: user.Username,
+		Currency: req.GetCurrency(),
+		Role:     user.Role,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.Internal, "failed to get limits: %s", err)
+	}
+
+	// lowering a limit is always allowed, only a raise depends on the user's standing
+	if raisesTransferLimit(current, perTransaction, daily, monthly) {
+		if err := policy.Check(user, policy.ActionRaiseLimit); err != nil {
+			return nil, policyError(user.Username, err)
+		}
+	}
+
+	_, err = server.store.UpsertTransferLimitOverride(ctx, db.UpsertTransferLimitOverrideParams{
+		Username:       user.Username,
+		Currency:       req.GetCurrency(),
+		PerTransaction: perTransaction,
+		Daily:          daily,
+		Monthly:        monthly,
+		UpdatedBy:      authPayload.Username,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to set transfer limit: %s", err)
+	}
+
+	limit, err := server.transferLimit(ctx, user, req.GetCurrency())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get limits: %s", err)
+	}
+
+	resp := &pb.SetTransferLimitResponse{
+		Limit: limit,
+	}
+	return resp, nil
+}
+
+// optionalLimit maps an unset limit to NULL, which means no limit
+func optionalLimit(value *int64) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *value, Valid: true}
+}
+
+func validateSetTransferLimitRequest(req *pb.SetTransferLimitRequest, currencies util.Currencies) (violations []*errdetails.BadRequest_FieldViolation) {
+	if (req.Username == nil) == (req.Role == nil) {
+		violations = append(violations, fieldViolations("username", errors.New("exactly one of username or role must be set")))
+	}
+	if req.Username != nil {
+		if err := util.ValidateUsername(req.GetUsername()); err != nil {
+			violations = append(violations, fieldViolations("username", err))
+		}
+	}
+	if req.Role != nil {
+		if role := util.Role(req.GetRole()); role != util.DepositorRole && role != util.BankerRole {
+			violations = append(violations, fieldViolations("role", fmt.Errorf("must be %s or %s", util.DepositorRole, util.BankerRole)))
+		}
+	}
+	if err := currencies.Validate(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolations("currency", err))
+	}
+	if req.PerTransaction != nil && req.GetPerTransaction() < 0 {
+		violations = append(violations, fieldViolations("per_transaction", errors.New("must not be negative")))
+	}
+	if req.Daily != nil && req.GetDaily() < 0 {
+		violations = append(violations, fieldViolations("daily", errors.New("must not be negative")))
+	}
+	if req.Monthly != nil && req.GetMonthly() < 0 {
+		violations = append(violations, fieldViolations("monthly", errors.New("must not be negative")))
+	}
+	return violations
+}
@@ -10,6 +10,7 @@ import (
 	db "github.com/guncv/Simple-Bank/db/sqlc"
 	"github.com/guncv/Simple-Bank/pb"
 	"github.com/guncv/Simple-Bank/util"
+	"github.com/guncv/Simple-Bank/worker"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -49,15 +50,23 @@ func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest)
 		}
 	}
 
-	arg := db.UpdateUserParams{
-		Username:         req.GetUsername(),
-		HashedPassword:   newPassword,
-		FullName:         toNullString(req.FullName),
-		Email:            toNullString(req.Email),
-		PasswordChangeAt: passwordChangeAt,
+	arg := db.UpdateUserTxParams{
+		UpdateUserParams: db.UpdateUserParams{
+			Username:         req.GetUsername(),
+			HashedPassword:   newPassword,
+			FullName:         toNullString(req.FullName),
+			Email:            toNullString(req.Email),
+			PasswordChangeAt: passwordChangeAt,
+		},
+		AfterEmailChange: func(user db.Users) (db.CreateOutboxMessageParams, error) {
+			taskPayload := &worker.PayloadSendVerifyEmail{
+				Username: user.Username,
+			}
+			return worker.NewOutboxMessage(worker.TaskSendVerifyEmail, taskPayload, worker.QueueCritical, 10)
+		},
 	}
 
-	user, err := server.store.UpdateUser(ctx, arg)
+	txResult, err := server.store.UpdateUserTx(ctx, arg)
 	if err != nil {
 		log.Printf("failed to update user: %s", err)
 		if err == sql.ErrNoRows {
@@ -66,8 +75,13 @@ func (server *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest)
 		return nil, status.Errorf(codes.Internal, "failed to update user: %s", err)
 	}
 
+	// every session of the user was blocked with the password change
+	if req.Password != nil {
+		server.sessionCache.invalidateUser(txResult.User.Username)
+	}
+
 	resp := &pb.UpdateUserResponse{
-		User: convertUser(user),
+		User: convertUser(txResult.User),
 	}
 
 	log.Printf("update user response: %v", resp)
@@ -3,6 +3,7 @@ package gapi
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -15,18 +16,20 @@ import (
 	"github.com/guncv/Simple-Bank/pb"
 	"github.com/guncv/Simple-Bank/token"
 	"github.com/guncv/Simple-Bank/util"
+	"github.com/guncv/Simple-Bank/worker"
+	mockworker "github.com/guncv/Simple-Bank/worker/mock"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type eqUpdateUserTxParamsMatcher struct {
-	arg      db.UpdateUserParams
+	arg      db.UpdateUserTxParams
 	password string
 }
 
 func (e eqUpdateUserTxParamsMatcher) Matches(x interface{}) bool {
-	actualArg, ok := x.(db.UpdateUserParams)
+	actualArg, ok := x.(db.UpdateUserTxParams)
 	if !ok {
 		return false
 	}
@@ -49,7 +52,7 @@ func (e eqUpdateUserTxParamsMatcher) String() string {
 	return fmt.Sprintf("matches arg %v and password %v", e.arg, e.password)
 }
 
-func EqUpdateUserTxParams(arg db.UpdateUserParams, password string) gomock.Matcher {
+func EqUpdateUserTxParams(arg db.UpdateUserTxParams, password string) gomock.Matcher {
 	return eqUpdateUserTxParamsMatcher{arg, password}
 }
 
@@ -81,16 +84,18 @@ func TestUpdateUserAPI(t *testing.T) {
 				hashedPassword, err := util.HashPassword(newPassword)
 				require.NoError(t, err)
 
-				arg := db.UpdateUserParams{
-					Username: user.Username,
-					FullName: sql.NullString{
-						String: newFullName,
-						Valid:  true,
-					},
-					Email: sql.NullString{},
-					HashedPassword: sql.NullString{
-						String: hashedPassword,
-						Valid:  true,
+				arg := db.UpdateUserTxParams{
+					UpdateUserParams: db.UpdateUserParams{
+						Username: user.Username,
+						FullName: sql.NullString{
+							String: newFullName,
+							Valid:  true,
+						},
+						Email: sql.NullString{},
+						HashedPassword: sql.NullString{
+							String: hashedPassword,
+							Valid:  true,
+						},
 					},
 				}
 
@@ -100,9 +105,9 @@ func TestUpdateUserAPI(t *testing.T) {
 				updatedUser.HashedPassword = hashedPassword
 
 				store.EXPECT().
-					UpdateUser(gomock.Any(), EqUpdateUserTxParams(arg, newPassword)).
+					UpdateUserTx(gomock.Any(), EqUpdateUserTxParams(arg, newPassword)).
 					Times(1).
-					Return(updatedUser, nil)
+					Return(db.UpdateUserTxResult{User: updatedUser}, nil)
 			},
 			buildContext: func(t *testing.T, token token.Maker) context.Context {
 				return newContextWithBearerToken(t, token, user.Username, util.Role(user.Role), time.Minute)
@@ -125,7 +130,7 @@ func TestUpdateUserAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Any()).
+					UpdateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			buildContext: func(t *testing.T, token token.Maker) context.Context {
@@ -149,26 +154,28 @@ func TestUpdateUserAPI(t *testing.T) {
 				hashedPassword, err := util.HashPassword(newPassword)
 				require.NoError(t, err)
 
-				arg := db.UpdateUserParams{
-					Username: user.Username,
-					FullName: sql.NullString{
-						String: newFullName,
-						Valid:  true,
-					},
-					Email: sql.NullString{
-						String: newEmail,
-						Valid:  true,
-					},
-					HashedPassword: sql.NullString{
-						String: hashedPassword,
-						Valid:  true,
+				arg := db.UpdateUserTxParams{
+					UpdateUserParams: db.UpdateUserParams{
+						Username: user.Username,
+						FullName: sql.NullString{
+							String: newFullName,
+							Valid:  true,
+						},
+						Email: sql.NullString{
+							String: newEmail,
+							Valid:  true,
+						},
+						HashedPassword: sql.NullString{
+							String: hashedPassword,
+							Valid:  true,
+						},
 					},
 				}
 
 				store.EXPECT().
-					UpdateUser(gomock.Any(), EqUpdateUserTxParams(arg, newPassword)).
+					UpdateUserTx(gomock.Any(), EqUpdateUserTxParams(arg, newPassword)).
 					Times(1).
-					Return(db.Users{}, errors.New("email already exists"))
+					Return(db.UpdateUserTxResult{}, errors.New("email already exists"))
 			},
 			buildContext: func(t *testing.T, token token.Maker) context.Context {
 				return newContextWithBearerToken(t, token, user.Username, util.Role(user.Role), time.Minute)
@@ -189,9 +196,9 @@ func TestUpdateUserAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Any()).
+					UpdateUserTx(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(db.Users{}, sql.ErrNoRows)
+					Return(db.UpdateUserTxResult{}, sql.ErrNoRows)
 			},
 			buildContext: func(t *testing.T, token token.Maker) context.Context {
 				return newContextWithBearerToken(t, token, user.Username, util.Role(user.Role), time.Minute)
@@ -212,7 +219,7 @@ func TestUpdateUserAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Any()).
+					UpdateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			buildContext: func(t *testing.T, token token.Maker) context.Context {
@@ -233,7 +240,7 @@ func TestUpdateUserAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Any()).
+					UpdateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			buildContext: func(t *testing.T, token token.Maker) context.Context {
@@ -254,7 +261,7 @@ func TestUpdateUserAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					UpdateUser(gomock.Any(), gomock.Any()).
+					UpdateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			buildContext: func(t *testing.T, token token.Maker) context.Context {
@@ -276,6 +283,7 @@ func TestUpdateUserAPI(t *testing.T) {
 			defer ctrl.Finish()
 
 			store := mockdb.NewMockStore(ctrl)
+			stubActiveSession(store)
 			tc.buildStubs(store)
 
 			server := newTestServer(t, store, nil)
@@ -285,3 +293,48 @@ func TestUpdateUserAPI(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateUserEmailChangeAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	newEmail := util.RandomEmail()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	stubActiveSession(store)
+	taskDistributor := mockworker.NewMockTaskDistributor(ctrl)
+
+	updatedUser := user
+	updatedUser.Email = newEmail
+	updatedUser.IsEmailVerified = false
+
+	store.EXPECT().
+		UpdateUserTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.UpdateUserTxParams) (db.UpdateUserTxResult, error) {
+			require.Equal(t, newEmail, arg.Email.String)
+			require.NotNil(t, arg.AfterEmailChange)
+			message, err := arg.AfterEmailChange(updatedUser)
+			require.NoError(t, err)
+			require.Equal(t, worker.TaskSendVerifyEmail, message.TaskType)
+			require.Equal(t, worker.QueueCritical, message.Queue)
+
+			var payload worker.PayloadSendVerifyEmail
+			require.NoError(t, json.Unmarshal(message.Payload, &payload))
+			require.Equal(t, user.Username, payload.Username)
+			return db.UpdateUserTxResult{User: updatedUser, EmailChanged: true}, nil
+		})
+
+	// the task is published by the outbox relay after the transaction commits,
+	// the mock distributor fails the test if the server enqueues anything itself
+	server := newTestServer(t, store, taskDistributor)
+	ctx := newContextWithBearerToken(t, server.tokenMaker, user.Username, util.Role(user.Role), time.Minute)
+	res, err := server.UpdateUser(ctx, &pb.UpdateUserRequest{
+		Username: user.Username,
+		Email:    &newEmail,
+	})
+	require.NoError(t, err)
+	require.Equal(t, newEmail, res.GetUser().GetEmail())
+	require.False(t, res.GetUser().GetIsEmailVerified())
+}
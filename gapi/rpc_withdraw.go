@@ -0,0 +1,48 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+	"github.com/guncv/Simple-Bank/util"
+)
+
+func (server *Server) Withdraw(ctx context.Context, req *pb.WithdrawRequest) (*pb.WithdrawResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []util.Role{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCashRequest(req.GetAccountId(), req.GetAmount(), req.GetCurrency(), server.currencies)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.validAccount(ctx, req.GetAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := server.checkCustomerAccount(ctx, account); err != nil {
+		return nil, err
+	}
+
+	result, err := server.store.WithdrawTx(ctx, db.CashTxParams{
+		AccountID:   req.GetAccountId(),
+		Amount:      req.GetAmount(),
+		InitiatedBy: authPayload.Username,
+	})
+	if err != nil {
+		return nil, cashTxError(err)
+	}
+
+	transfer, updatedAccount, entry := convertCashTxResult(server.currencies, result)
+	resp := &pb.WithdrawResponse{
+		Transfer: transfer,
+		Account:  updatedAccount,
+		Entry:    entry,
+	}
+
+	return resp, nil
+}
@@ -17,20 +17,29 @@ type Server struct {
 	store           db.Store
 	tokenMaker      token.Maker
 	taskDistributor worker.TaskDistributor
+	sessionCache    *sessionCache
+	currencies      util.Currencies
 }
 
 // New Server creates a new gRPC server
 func NewServer(taskDistributor worker.TaskDistributor, config util.Config, store db.Store) (*Server, error) {
-	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+	tokenMaker, err := token.NewMaker(config)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
 
+	currencies := config.CurrencyRegistry
+	if currencies == nil {
+		currencies = util.NewDefaultCurrencies()
+	}
+
 	server := &Server{
 		config:          config,
 		store:           store,
 		tokenMaker:      tokenMaker,
 		taskDistributor: taskDistributor,
+		sessionCache:    newSessionCache(sessionCacheTTL),
+		currencies:      currencies,
 	}
 
 	return server, nil
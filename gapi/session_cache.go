@@ -0,0 +1,87 @@
+package gapi
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+)
+
+// sessionCacheTTL bounds how long a revoked session or a password change can go unnoticed by another server instance
+const sessionCacheTTL = 10 * time.Second
+
+type sessionCacheEntry struct {
+	info      db.GetSessionAuthInfoRow
+	expiresAt time.Time
+}
+
+// sessionCache keeps the session state used by authorizeUser for a short time to avoid a DB hit on every call
+type sessionCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[uuid.UUID]sessionCacheEntry
+}
+
+func newSessionCache(ttl time.Duration) *sessionCache {
+	return &sessionCache{
+		ttl:     ttl,
+		entries: make(map[uuid.UUID]sessionCacheEntry),
+	}
+}
+
+func (cache *sessionCache) get(sessionID uuid.UUID) (db.GetSessionAuthInfoRow, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[sessionID]
+	if !ok {
+		return db.GetSessionAuthInfoRow{}, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(cache.entries, sessionID)
+		return db.GetSessionAuthInfoRow{}, false
+	}
+	return entry.info, true
+}
+
+func (cache *sessionCache) set(sessionID uuid.UUID, info db.GetSessionAuthInfoRow) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	now := time.Now()
+	for id, entry := range cache.entries {
+		if now.After(entry.expiresAt) {
+			delete(cache.entries, id)
+		}
+	}
+
+	cache.entries[sessionID] = sessionCacheEntry{
+		info:      info,
+		expiresAt: now.Add(cache.ttl),
+	}
+}
+
+// invalidateFamily drops every cached session that belongs to the given family
+func (cache *sessionCache) invalidateFamily(familyID uuid.UUID) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	for id, entry := range cache.entries {
+		if entry.info.FamilyID == familyID {
+			delete(cache.entries, id)
+		}
+	}
+}
+
+// invalidateUser drops every cached session of the given user
+func (cache *sessionCache) invalidateUser(username string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	for id, entry := range cache.entries {
+		if entry.info.Username == username {
+			delete(cache.entries, id)
+		}
+	}
+}
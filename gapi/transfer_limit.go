@@ -0,0 +1,65 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/pb"
+)
+
+// transferLimit reports the limits in effect for the user and currency with the allowance left
+func (server *Server) transferLimit(ctx context.Context, user db.Users, currency string) (*pb.TransferLimit, error) {
+	effective, err := server.store.GetEffectiveTransferLimit(ctx, db.GetEffectiveTransferLimitParams{
+		Username: user.Username,
+		Currency: currency,
+		Role:     user.Role,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	totals, err := server.store.GetTransferTotals(ctx, db.GetTransferTotalsParams{
+		Owner:    user.Username,
+		Currency: currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	limit := &pb.TransferLimit{
+		Currency:    currency,
+		Overridden:  effective.Overridden,
+		DailyUsed:   totals.DailyTotal,
+		MonthlyUsed: totals.MonthlyTotal,
+	}
+	if effective.PerTransaction.Valid {
+		limit.PerTransaction = &effective.PerTransaction.Int64
+	}
+	if effective.Daily.Valid {
+		remaining := max(effective.Daily.Int64-totals.DailyTotal, 0)
+		limit.Daily = &effective.Daily.Int64
+		limit.DailyRemaining = &remaining
+	}
+	if effective.Monthly.Valid {
+		remaining := max(effective.Monthly.Int64-totals.MonthlyTotal, 0)
+		limit.Monthly = &effective.Monthly.Int64
+		limit.MonthlyRemaining = &remaining
+	}
+	return limit, nil
+}
+
+// raisesTransferLimit reports whether any of the new limits allows more than the current one. A NULL limit means no limit.
+func raisesTransferLimit(current db.GetEffectiveTransferLimitRow, perTransaction, daily, monthly sql.NullInt64) bool {
+	return raisesLimit(current.PerTransaction, perTransaction) ||
+		raisesLimit(current.Daily, daily) ||
+		raisesLimit(current.Monthly, monthly)
+}
+
+func raisesLimit(current, next sql.NullInt64) bool {
+	if !current.Valid {
+		return false
+	}
+	return !next.Valid || next.Int64 > current.Int64
+}
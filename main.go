@@ -3,7 +3,9 @@ package main
 import (
 	"context"
 	"database/sql"
+	"expvar"
 	"os"
+	"strings"
 
 	"net"
 	"net/http"
@@ -50,6 +52,7 @@ func main() {
 	runDBMigration(config.MigrationsURL, config.DBSource)
 
 	store := db.NewStore(conn)
+	provisionSystemAccounts(store, config)
 
 	log.Info().Msgf("redis address: %s", config.RedisAddress)
 	redisOpt := asynq.RedisClientOpt{
@@ -58,13 +61,28 @@ func main() {
 
 	taskDistributor := worker.NewRedisTaskDistributor(redisOpt)
 
-	go runTaskProcessor(redisOpt, store, config)
+	go runTaskProcessor(redisOpt, store, taskDistributor, config)
+	go runTaskScheduler(redisOpt, config)
+	go runOutboxRelay(store, taskDistributor, config)
+	go runAdminServer(config)
 	go runGatewayServer(taskDistributor, config, store)
 	runGrpcServer(taskDistributor, config, store)
 	// runGinServer(config, store)
 
 }
 
+// provisionSystemAccounts makes sure every configured currency has its cash, FX and revenue accounts
+func provisionSystemAccounts(store db.Store, config util.Config) {
+	result, err := store.ProvisionSystemAccountsTx(context.Background(), db.ProvisionSystemAccountsTxParams{
+		Currencies: config.CurrencyRegistry.Codes(),
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot provision system accounts")
+	}
+
+	log.Info().Int64("created", result.Created).Msg("system accounts provisioned")
+}
+
 func runDBMigration(migrationURL string, dbSource string) {
 	migration, err := migrate.New(migrationURL, dbSource)
 	if err != nil {
@@ -101,9 +119,9 @@ func runGrpcServer(taskDistributor worker.TaskDistributor, config util.Config, s
 	}
 }
 
-func runTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, config util.Config) {
+func runTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, taskDistributor worker.TaskDistributor, config util.Config) {
 	mailer := mail.NewGmailSender(config.EmailSenderName, config.EmailSenderAddress, config.EmailSenderPassword)
-	taskProcessor := worker.NewRedisTaskProcessor(redisOpt, store, mailer)
+	taskProcessor := worker.NewRedisTaskProcessor(redisOpt, store, mailer, taskDistributor, config.StatementDir, config.CurrencyRegistry)
 
 	log.Info().Msg("task processor started")
 
@@ -112,6 +130,37 @@ func runTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, config util
 	}
 }
 
+func runTaskScheduler(redisOpt asynq.RedisClientOpt, config util.Config) {
+	taskScheduler := worker.NewRedisTaskScheduler(redisOpt, worker.PeriodicTasks(config))
+
+	if err := taskScheduler.Start(); err != nil {
+		log.Fatal().Err(err).Msg("fail to start task scheduler")
+	}
+}
+
+func runOutboxRelay(store db.Store, taskDistributor worker.TaskDistributor, config util.Config) {
+	relay := worker.NewOutboxRelay(store, taskDistributor, config.OutboxRelayInterval)
+
+	log.Info().Msg("outbox relay started")
+	relay.Start(context.Background())
+}
+
+// runAdminServer serves the runtime metrics on an internal address that is not published with the gateway
+func runAdminServer(config util.Config) {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	listener, err := net.Listen("tcp", config.AdminServerAddress)
+	if err != nil {
+		log.Fatal().Msg("cannot create admin listener")
+	}
+
+	log.Info().Msgf("start admin server at %s", listener.Addr().String())
+	if err := http.Serve(listener, mux); err != nil {
+		log.Fatal().Msg("cannot start admin server")
+	}
+}
+
 func runGatewayServer(taskDistributor worker.TaskDistributor, config util.Config, store db.Store) {
 	server, err := gapi.NewServer(taskDistributor, config, store)
 	if err != nil {
@@ -126,7 +175,15 @@ func runGatewayServer(taskDistributor worker.TaskDistributor, config util.Config
 		},
 	})
 
-	grpcMux := runtime.NewServeMux(jsonOption)
+	// forward the Idempotency-Key header to the handlers as incoming metadata
+	headerMatcherOption := runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+		if strings.EqualFold(key, "Idempotency-Key") {
+			return key, true
+		}
+		return runtime.DefaultHeaderMatcher(key)
+	})
+
+	grpcMux := runtime.NewServeMux(jsonOption, headerMatcherOption)
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -137,6 +194,7 @@ func runGatewayServer(taskDistributor worker.TaskDistributor, config util.Config
 
 	mux := http.NewServeMux()
 	mux.Handle("/", grpcMux)
+	mux.Handle(gapi.JWKSPath, server.JWKSHandler())
 
 	statikFS, err := fs.New()
 	if err != nil {
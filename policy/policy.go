@@ -0,0 +1,37 @@
+// Package policy decides which actions a user is allowed to take based on
+// the state of their profile, independently of the transport serving the request.
+package policy
+
+import (
+	"errors"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+)
+
+// Action is an operation that may be restricted by policy. Logging in and opening
+// accounts are never restricted, so they have no action.
+type Action string
+
+const (
+	ActionTransfer   Action = "transfer"
+	ActionRaiseLimit Action = "raise_limit"
+)
+
+// ViolationEmailNotVerified identifies ErrEmailNotVerified in error details
+const ViolationEmailNotVerified = "EMAIL_NOT_VERIFIED"
+
+var ErrEmailNotVerified = errors.New("email address is not verified: use the link sent to your email or request a new one with ResendVerifyEmail")
+
+// verifiedEmailActions move money or raise how much money can be moved
+var verifiedEmailActions = map[Action]bool{
+	ActionTransfer:   true,
+	ActionRaiseLimit: true,
+}
+
+// Check returns an error if the user is not allowed to perform the action
+func Check(user db.Users, action Action) error {
+	if verifiedEmailActions[action] && !user.IsEmailVerified {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
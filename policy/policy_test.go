@@ -0,0 +1,34 @@
+package policy
+
+import (
+	"testing"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	unverified := db.Users{Username: "alice"}
+	verified := db.Users{Username: "bob", IsEmailVerified: true}
+
+	testCases := []struct {
+		name   string
+		user   db.Users
+		action Action
+		err    error
+	}{
+		{"UnverifiedTransfer", unverified, ActionTransfer, ErrEmailNotVerified},
+		{"UnverifiedRaiseLimit", unverified, ActionRaiseLimit, ErrEmailNotVerified},
+		{"VerifiedTransfer", verified, ActionTransfer, nil},
+		{"VerifiedRaiseLimit", verified, ActionRaiseLimit, nil},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			err := Check(tc.user, tc.action)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/guncv/Simple-Bank/util"
+)
+
+const dateLayout = "2006-01-02"
+
+// WriteCSV writes the statement as CSV with the opening and closing balances as the first and last rows
+func WriteCSV(w io.Writer, statement *Statement) error {
+	writer := csv.NewWriter(w)
+
+	amount := func(value int64) string {
+		return util.FormatDecimal(value, statement.MinorUnit)
+	}
+
+	records := [][]string{
+		{"date", "entry_id", "description", "amount", "balance", "currency"},
+		{statement.Period.Start.Format(dateLayout), "", "Opening balance", "", amount(statement.OpeningBalance), statement.Currency},
+	}
+	for _, line := range statement.Lines {
+		records = append(records, []string{
+			line.Date.UTC().Format(dateLayout),
+			strconv.FormatInt(line.EntryID, 10),
+			description(line.Amount),
+			amount(line.Amount),
+			amount(line.Balance),
+			statement.Currency,
+		})
+	}
+	records = append(records, []string{
+		statement.Period.End.AddDate(0, 0, -1).Format(dateLayout), "", "Closing balance", "", amount(statement.ClosingBalance), statement.Currency,
+	})
+
+	if err := writer.WriteAll(records); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+func description(amount int64) string {
+	if amount < 0 {
+		return "Debit"
+	}
+	return "Credit"
+}
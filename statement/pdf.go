@@ -0,0 +1,140 @@
+package statement
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/guncv/Simple-Bank/util"
+)
+
+// A4 page in points, text is set in Courier so that columns line up without measuring glyphs
+const (
+	pageWidth    = 595
+	pageHeight   = 842
+	pageMargin   = 50
+	fontSize     = 9
+	lineHeight   = 12
+	linesPerPage = (pageHeight - 2*pageMargin) / lineHeight
+)
+
+// WritePDF writes the statement as a plain text PDF document
+func WritePDF(w io.Writer, statement *Statement) error {
+	lines := pdfLines(statement)
+
+	var pages [][]string
+	for len(lines) > linesPerPage {
+		pages = append(pages, lines[:linesPerPage])
+		lines = lines[linesPerPage:]
+	}
+	pages = append(pages, lines)
+
+	_, err := w.Write(renderPDF(pages))
+	return err
+}
+
+func pdfLines(statement *Statement) []string {
+	amount := func(value int64) string {
+		return util.FormatDecimal(value, statement.MinorUnit)
+	}
+	row := func(date string, entryID string, description string, amount string, balance string) string {
+		return fmt.Sprintf("%-10s  %10s  %-15s  %16s  %16s", date, entryID, description, amount, balance)
+	}
+
+	lines := []string{
+		"Simple Bank - Account Statement",
+		"",
+		fmt.Sprintf("Account:  %d (%s)", statement.AccountID, statement.Currency),
+		fmt.Sprintf("Holder:   %s (%s)", statement.FullName, statement.Owner),
+		fmt.Sprintf("Period:   %s to %s",
+			statement.Period.Start.Format(dateLayout),
+			statement.Period.End.AddDate(0, 0, -1).Format(dateLayout),
+		),
+		"",
+		row("Date", "Entry", "Description", "Amount", "Balance"),
+		strings.Repeat("-", 75),
+		row(statement.Period.Start.Format(dateLayout), "", "Opening balance", "", amount(statement.OpeningBalance)),
+	}
+	for _, line := range statement.Lines {
+		lines = append(lines, row(
+			line.Date.UTC().Format(dateLayout),
+			strconv.FormatInt(line.EntryID, 10),
+			description(line.Amount),
+			amount(line.Amount),
+			amount(line.Balance),
+		))
+	}
+	lines = append(lines,
+		row(statement.Period.End.AddDate(0, 0, -1).Format(dateLayout), "", "Closing balance", "", amount(statement.ClosingBalance)),
+	)
+
+	return lines
+}
+
+// renderPDF lays out one content stream per page and writes the objects followed by their cross-reference table
+func renderPDF(pages [][]string) []byte {
+	var objects []string
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+	)
+
+	for i, lines := range pages {
+		var content strings.Builder
+		fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", fontSize, lineHeight, pageMargin, pageHeight-pageMargin)
+		for _, line := range lines {
+			fmt.Fprintf(&content, "(%s) '\n", escapePDFText(line))
+		}
+		content.WriteString("ET")
+
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+				pageWidth, pageHeight, 5+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes()
+}
+
+// escapePDFText escapes a string for a PDF literal, characters outside ASCII are replaced since the font is not embedded
+func escapePDFText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
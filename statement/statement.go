@@ -0,0 +1,86 @@
+package statement
+
+import (
+	"fmt"
+	"time"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+)
+
+// Period is the half-open time range [Start, End) covered by a statement
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// MonthOf returns the calendar month in UTC that contains t
+func MonthOf(t time.Time) Period {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return Period{Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+// PreviousMonth returns the last complete calendar month before t
+func PreviousMonth(t time.Time) Period {
+	return MonthOf(MonthOf(t).Start.AddDate(0, 0, -1))
+}
+
+// String names the period after its month, e.g. "2024-03"
+func (period Period) String() string {
+	return period.Start.Format("2006-01")
+}
+
+// Line is one entry of the statement with the balance right after it
+type Line struct {
+	EntryID int64
+	Date    time.Time
+	Amount  int64
+	Balance int64
+}
+
+// Statement lists the entries of an account over a period between its opening and closing balances
+type Statement struct {
+	AccountID      int64
+	Owner          string
+	FullName       string
+	Currency       string
+	MinorUnit      int
+	Period         Period
+	OpeningBalance int64
+	ClosingBalance int64
+	Lines          []Line
+}
+
+// New builds the statement of an account from its balance at the start of the period and the entries booked during it.
+// Amounts are rendered with minorUnit decimal places.
+func New(account db.Accounts, owner db.Users, period Period, openingBalance int64, entries []db.Entries, minorUnit int) *Statement {
+	statement := &Statement{
+		AccountID:      account.ID,
+		Owner:          owner.Username,
+		FullName:       owner.FullName,
+		Currency:       account.Currency,
+		MinorUnit:      minorUnit,
+		Period:         period,
+		OpeningBalance: openingBalance,
+		Lines:          make([]Line, 0, len(entries)),
+	}
+
+	balance := openingBalance
+	for _, entry := range entries {
+		balance += entry.Amount
+		statement.Lines = append(statement.Lines, Line{
+			EntryID: entry.ID,
+			Date:    entry.CreatedAt,
+			Amount:  entry.Amount,
+			Balance: balance,
+		})
+	}
+	statement.ClosingBalance = balance
+
+	return statement
+}
+
+// FileName returns the name of the statement file without extension
+func (statement *Statement) FileName() string {
+	return fmt.Sprintf("statement-%d-%s", statement.AccountID, statement.Period)
+}
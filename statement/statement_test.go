@@ -0,0 +1,119 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomStatement(t *testing.T, n int) *Statement {
+	account := db.Accounts{ID: util.RandomInt(1, 1000), Currency: util.USD}
+	owner := db.Users{Username: util.RandomOwner(), FullName: "Jane (Doe)"}
+	period := MonthOf(time.Date(2024, time.March, 15, 10, 0, 0, 0, time.UTC))
+
+	entries := make([]db.Entries, n)
+	for i := range entries {
+		entries[i] = db.Entries{
+			ID:        int64(i + 1),
+			AccountID: account.ID,
+			Amount:    util.RandomInt(-1000, 1000),
+			CreatedAt: period.Start.Add(time.Duration(i) * time.Hour),
+		}
+	}
+
+	return New(account, owner, period, 10000, entries, 2)
+}
+
+func TestPeriod(t *testing.T) {
+	period := MonthOf(time.Date(2024, time.December, 31, 23, 59, 0, 0, time.UTC))
+	require.Equal(t, time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC), period.Start)
+	require.Equal(t, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), period.End)
+	require.Equal(t, "2024-12", period.String())
+
+	previous := PreviousMonth(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, period, previous)
+}
+
+func TestNew(t *testing.T) {
+	statement := randomStatement(t, 5)
+	require.Len(t, statement.Lines, 5)
+
+	balance := statement.OpeningBalance
+	for _, line := range statement.Lines {
+		balance += line.Amount
+		require.Equal(t, balance, line.Balance)
+	}
+	require.Equal(t, balance, statement.ClosingBalance)
+}
+
+func TestWriteCSV(t *testing.T) {
+	statement := randomStatement(t, 3)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, statement))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, len(statement.Lines)+3)
+
+	require.Equal(t, "Opening balance", records[1][2])
+	require.Equal(t, "100.00", records[1][4])
+	require.Equal(t, "2024-03-01", records[1][0])
+
+	last := records[len(records)-1]
+	require.Equal(t, "Closing balance", last[2])
+	require.Equal(t, "2024-03-31", last[0])
+	require.Equal(t, util.FormatDecimal(statement.ClosingBalance, 2), last[4])
+
+	require.Equal(t, strconv.FormatInt(statement.Lines[0].EntryID, 10), records[2][1])
+}
+
+func TestWritePDF(t *testing.T) {
+	// enough lines to span several pages
+	statement := randomStatement(t, 2*linesPerPage)
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePDF(&buf, statement))
+
+	pdf := buf.Bytes()
+	require.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	require.Contains(t, string(pdf), "/Count 3")
+	require.Contains(t, string(pdf), `Jane \(Doe\)`)
+
+	// every cross-reference offset must point at its object
+	offsets := regexp.MustCompile(`(?m)^(\d{10}) 00000 n $`).FindAllSubmatch(pdf, -1)
+	require.NotEmpty(t, offsets)
+	for i, match := range offsets {
+		offset, err := strconv.Atoi(string(match[1]))
+		require.NoError(t, err)
+		require.True(t, bytes.HasPrefix(pdf[offset:], []byte(strconv.Itoa(i+1)+" 0 obj")))
+	}
+}
+
+func TestSave(t *testing.T) {
+	statement := randomStatement(t, 2)
+	dir := t.TempDir()
+
+	paths, err := Save(dir, statement)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, statement.Owner, statement.FileName()+".csv"),
+		filepath.Join(dir, statement.Owner, statement.FileName()+".pdf"),
+	}, paths)
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.NotZero(t, info.Size())
+	}
+}
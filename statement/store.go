@@ -0,0 +1,42 @@
+package statement
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Save writes the CSV and PDF renderings of the statement under dir, grouped by account owner,
+// and returns the paths of the written files
+func Save(dir string, statement *Statement) ([]string, error) {
+	dir = filepath.Join(dir, statement.Owner)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create statement directory: %w", err)
+	}
+
+	csvPath := filepath.Join(dir, statement.FileName()+".csv")
+	if err := writeFile(csvPath, func(file *os.File) error { return WriteCSV(file, statement) }); err != nil {
+		return nil, err
+	}
+
+	pdfPath := filepath.Join(dir, statement.FileName()+".pdf")
+	if err := writeFile(pdfPath, func(file *os.File) error { return WritePDF(file, statement) }); err != nil {
+		return nil, err
+	}
+
+	return []string{csvPath, pdfPath}, nil
+}
+
+func writeFile(path string, write func(file *os.File) error) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	if err := write(file); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return file.Close()
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
 	"github.com/guncv/Simple-Bank/util"
 )
 
@@ -25,9 +26,9 @@ func NewJWTMaker(secretKey string) (Maker, error) {
 	return &JWTMaker{secretKey}, nil
 }
 
-// CreateToken creates a new token for a specific username and duration
-func (maker *JWTMaker) CreateToken(username string, role util.Role, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, role, duration)
+// CreateToken creates a new token for a specific username, session and duration
+func (maker *JWTMaker) CreateToken(username string, role util.Role, sessionID uuid.UUID, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, sessionID, duration)
 	if err != nil {
 		return "", nil, err
 	}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
 	"github.com/guncv/Simple-Bank/util"
 	"github.com/stretchr/testify/require"
 )
@@ -15,12 +16,13 @@ func TestJWTMaker(t *testing.T) {
 
 	username := util.RandomOwner()
 	role := util.DepositorRole
+	sessionID := uuid.New()
 	duration := time.Minute
 
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
 
-	token, payload, err := maker.CreateToken(username, role, duration)
+	token, payload, err := maker.CreateToken(username, role, sessionID, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -41,9 +43,10 @@ func TestExpiredJWTToken(t *testing.T) {
 
 	username := util.RandomOwner()
 	role := util.DepositorRole
+	sessionID := uuid.New()
 	duration := -time.Minute
 
-	token, payload, err := maker.CreateToken(username, role, duration)
+	token, payload, err := maker.CreateToken(username, role, sessionID, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -55,7 +58,7 @@ func TestExpiredJWTToken(t *testing.T) {
 }
 
 func TestInvalidJWTTokenALgNone(t *testing.T) {
-	payload, err := NewPayload(util.RandomOwner(), util.DepositorRole, time.Minute)
+	payload, err := NewPayload(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute)
 	require.NoError(t, err)
 
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, payload)
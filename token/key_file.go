@@ -0,0 +1,69 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ed25519KeyFileEntry is a key as stored in the TOKEN_KEYS_FILE json file.
+// Keys are base64 encoded; the private key may be either the 32-byte seed or the full 64-byte key.
+type ed25519KeyFileEntry struct {
+	ID         string    `json:"kid"`
+	PrivateKey string    `json:"private_key,omitempty"`
+	PublicKey  string    `json:"public_key,omitempty"`
+	NotBefore  time.Time `json:"not_before"`
+	NotAfter   time.Time `json:"not_after,omitempty"`
+}
+
+// LoadEd25519Keys reads the key set of the PASETO v4.public maker from a json file
+func LoadEd25519Keys(path string) ([]Ed25519Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read key file: %w", err)
+	}
+
+	var entries []ed25519KeyFileEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("cannot parse key file: %w", err)
+	}
+
+	keys := make([]Ed25519Key, 0, len(entries))
+	for _, entry := range entries {
+		key := Ed25519Key{
+			ID:        entry.ID,
+			NotBefore: entry.NotBefore,
+			NotAfter:  entry.NotAfter,
+		}
+
+		if entry.PrivateKey != "" {
+			privateKey, err := base64.StdEncoding.DecodeString(entry.PrivateKey)
+			if err != nil {
+				return nil, fmt.Errorf("invalid private key for key %q: %w", entry.ID, err)
+			}
+			switch len(privateKey) {
+			case ed25519.SeedSize:
+				key.PrivateKey = ed25519.NewKeyFromSeed(privateKey)
+			case ed25519.PrivateKeySize:
+				key.PrivateKey = ed25519.PrivateKey(privateKey)
+			default:
+				return nil, fmt.Errorf("invalid private key size for key %q", entry.ID)
+			}
+		}
+
+		if entry.PublicKey != "" {
+			publicKey, err := base64.StdEncoding.DecodeString(entry.PublicKey)
+			if err != nil {
+				return nil, fmt.Errorf("invalid public key for key %q: %w", entry.ID, err)
+			}
+			key.PublicKey = ed25519.PublicKey(publicKey)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
@@ -1,16 +1,33 @@
 package token
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/guncv/Simple-Bank/util"
 )
 
 // Maker is an interface for managing tokens
 type Maker interface {
-	// CreateToken creates a new token for a specific username and duration
-	CreateToken(username string, role util.Role, duration time.Duration) (string, *Payload, error)
+	// CreateToken creates a new token for a specific username, session and duration
+	CreateToken(username string, role util.Role, sessionID uuid.UUID, duration time.Duration) (string, *Payload, error)
 
 	// Verify Token
 	VerifyToken(token string) (*Payload, error)
 }
+
+// NewMaker creates the token maker selected by the config: PASETO v4.public when a key file is
+// configured, otherwise the symmetric PASETO v2.local maker
+func NewMaker(config util.Config) (Maker, error) {
+	if config.TokenKeysFile == "" {
+		return NewPasetoMaker(config.TokenSymmetricKey)
+	}
+
+	keys, err := LoadEd25519Keys(config.TokenKeysFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load token keys: %w", err)
+	}
+
+	return NewPasetoV4Maker(keys, config.TokenSigningKeyID)
+}
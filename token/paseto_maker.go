@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/aead/chacha20poly1305"
+	"github.com/google/uuid"
 	"github.com/guncv/Simple-Bank/util"
 	"github.com/o1egl/paseto"
 )
@@ -27,9 +28,9 @@ func NewPasetoMaker(symmetricKey string) (Maker, error) {
 	return maker, nil
 }
 
-// CreateToken creates a new token for a specific username and duration
-func (maker *PasetoMaker) CreateToken(username string, role util.Role, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, role, duration)
+// CreateToken creates a new token for a specific username, session and duration
+func (maker *PasetoMaker) CreateToken(username string, role util.Role, sessionID uuid.UUID, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, sessionID, duration)
 	if err != nil {
 		return "", nil, err
 	}
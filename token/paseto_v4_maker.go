@@ -0,0 +1,255 @@
+package token
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guncv/Simple-Bank/util"
+)
+
+const pasetoV4PublicHeader = "v4.public."
+
+// Ed25519Key is a key of the PASETO v4.public key set. Keys without a private key can only verify tokens.
+type Ed25519Key struct {
+	ID         string
+	PrivateKey ed25519.PrivateKey
+	PublicKey  ed25519.PublicKey
+	NotBefore  time.Time
+	// NotAfter is the time after which tokens signed by this key are rejected, zero means no limit
+	NotAfter time.Time
+}
+
+// active reports whether the key can be used at the given time
+func (key *Ed25519Key) active(now time.Time) bool {
+	if now.Before(key.NotBefore) {
+		return false
+	}
+	return key.NotAfter.IsZero() || now.Before(key.NotAfter)
+}
+
+type pasetoV4Footer struct {
+	KeyID string `json:"kid"`
+}
+
+// PasetoV4Maker is a PASETO v4.public token maker using Ed25519 signatures
+type PasetoV4Maker struct {
+	keys         map[string]*Ed25519Key
+	currentKeyID string
+}
+
+// NewPasetoV4Maker creates a new PasetoV4Maker. Tokens are signed with the current key until a key with a
+// private key and a later NotBefore becomes active, which allows rotation to be scheduled in advance.
+// If currentKeyID is empty the newest active signing key is used.
+func NewPasetoV4Maker(keys []Ed25519Key, currentKeyID string) (Maker, error) {
+	maker := &PasetoV4Maker{
+		keys:         make(map[string]*Ed25519Key, len(keys)),
+		currentKeyID: currentKeyID,
+	}
+
+	for i := range keys {
+		key := keys[i]
+		if key.ID == "" {
+			return nil, fmt.Errorf("key id must not be empty")
+		}
+		if _, ok := maker.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+
+		if key.PrivateKey != nil {
+			if len(key.PrivateKey) != ed25519.PrivateKeySize {
+				return nil, fmt.Errorf("invalid private key size for key %q", key.ID)
+			}
+			key.PublicKey = key.PrivateKey.Public().(ed25519.PublicKey)
+		}
+		if len(key.PublicKey) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid public key size for key %q", key.ID)
+		}
+
+		maker.keys[key.ID] = &key
+	}
+
+	if currentKeyID != "" {
+		key, ok := maker.keys[currentKeyID]
+		if !ok {
+			return nil, fmt.Errorf("current key %q not found", currentKeyID)
+		}
+		if key.PrivateKey == nil {
+			return nil, fmt.Errorf("current key %q has no private key", currentKeyID)
+		}
+	}
+
+	return maker, nil
+}
+
+// signingKey returns the key used to sign new tokens at the given time
+func (maker *PasetoV4Maker) signingKey(now time.Time) (*Ed25519Key, error) {
+	var floor time.Time
+	if current, ok := maker.keys[maker.currentKeyID]; ok {
+		floor = current.NotBefore
+	}
+
+	var signingKey *Ed25519Key
+	for _, key := range maker.keys {
+		if key.PrivateKey == nil || !key.active(now) || key.NotBefore.Before(floor) {
+			continue
+		}
+		if signingKey == nil || key.NotBefore.After(signingKey.NotBefore) ||
+			(key.NotBefore.Equal(signingKey.NotBefore) && key.ID > signingKey.ID) {
+			signingKey = key
+		}
+	}
+
+	if signingKey == nil {
+		return nil, fmt.Errorf("no active signing key")
+	}
+	return signingKey, nil
+}
+
+// CreateToken creates a new token for a specific username, session and duration
+func (maker *PasetoV4Maker) CreateToken(username string, role util.Role, sessionID uuid.UUID, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, sessionID, duration)
+	if err != nil {
+		return "", nil, err
+	}
+
+	key, err := maker.signingKey(payload.IssuedAt)
+	if err != nil {
+		return "", nil, err
+	}
+
+	message, err := json.Marshal(payload)
+	if err != nil {
+		return "", nil, err
+	}
+
+	footer, err := json.Marshal(pasetoV4Footer{KeyID: key.ID})
+	if err != nil {
+		return "", nil, err
+	}
+
+	signature := ed25519.Sign(key.PrivateKey, pae([]byte(pasetoV4PublicHeader), message, footer, nil))
+
+	token := pasetoV4PublicHeader +
+		base64.RawURLEncoding.EncodeToString(append(message, signature...)) + "." +
+		base64.RawURLEncoding.EncodeToString(footer)
+
+	return token, payload, nil
+}
+
+// VerifyToken checks if the token is valid or not
+func (maker *PasetoV4Maker) VerifyToken(token string) (*Payload, error) {
+	if !strings.HasPrefix(token, pasetoV4PublicHeader) {
+		return nil, ErrInvalidToken
+	}
+
+	parts := strings.Split(token[len(pasetoV4PublicHeader):], ".")
+	if len(parts) != 2 {
+		return nil, ErrInvalidToken
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || len(body) < ed25519.SignatureSize {
+		return nil, ErrInvalidToken
+	}
+
+	footer, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var f pasetoV4Footer
+	if err := json.Unmarshal(footer, &f); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	key, ok := maker.keys[f.KeyID]
+	if !ok || !key.active(time.Now()) {
+		return nil, ErrInvalidToken
+	}
+
+	message := body[:len(body)-ed25519.SignatureSize]
+	signature := body[len(body)-ed25519.SignatureSize:]
+	if !ed25519.Verify(key.PublicKey, pae([]byte(pasetoV4PublicHeader), message, footer, nil), signature) {
+		return nil, ErrInvalidToken
+	}
+
+	payload := &Payload{}
+	if err := json.Unmarshal(message, payload); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if err := payload.Valid(); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// pae implements the PASETO Pre-Authentication Encoding
+func pae(pieces ...[]byte) []byte {
+	var buf bytes.Buffer
+	le64 := func(n int) {
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], uint64(n)&^(1<<63))
+		buf.Write(b[:])
+	}
+
+	le64(len(pieces))
+	for _, piece := range pieces {
+		le64(len(piece))
+		buf.Write(piece)
+	}
+
+	return buf.Bytes()
+}
+
+// PublicKey is a verification key published for other services
+type PublicKey struct {
+	ID        string
+	Algorithm string
+	Key       ed25519.PublicKey
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// PublicKeyProvider is implemented by makers whose tokens can be verified with public keys
+type PublicKeyProvider interface {
+	PublicKeys() []PublicKey
+}
+
+// PublicKeys returns every key that is active or scheduled, ordered by NotBefore,
+// so that verifiers can cache keys before they start being used
+func (maker *PasetoV4Maker) PublicKeys() []PublicKey {
+	now := time.Now()
+
+	keys := make([]PublicKey, 0, len(maker.keys))
+	for _, key := range maker.keys {
+		if !key.NotAfter.IsZero() && !now.Before(key.NotAfter) {
+			continue
+		}
+		keys = append(keys, PublicKey{
+			ID:        key.ID,
+			Algorithm: "v4.public",
+			Key:       key.PublicKey,
+			NotBefore: key.NotBefore,
+			NotAfter:  key.NotAfter,
+		})
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].NotBefore.Equal(keys[j].NotBefore) {
+			return keys[i].ID < keys[j].ID
+		}
+		return keys[i].NotBefore.Before(keys[j].NotBefore)
+	})
+
+	return keys
+}
@@ -0,0 +1,207 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomEd25519Key(t *testing.T, id string, notBefore time.Time) Ed25519Key {
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	return Ed25519Key{
+		ID:         id,
+		PrivateKey: privateKey,
+		NotBefore:  notBefore,
+	}
+}
+
+func TestPasetoV4Maker(t *testing.T) {
+	key := randomEd25519Key(t, "key-1", time.Now().Add(-time.Hour))
+	maker, err := NewPasetoV4Maker([]Ed25519Key{key}, key.ID)
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	role := util.DepositorRole
+	sessionID := uuid.New()
+	duration := time.Minute
+
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	token, payload, err := maker.CreateToken(username, role, sessionID, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, payload)
+	require.True(t, strings.HasPrefix(token, pasetoV4PublicHeader))
+
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+
+	require.NotZero(t, payload.ID)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
+	require.Equal(t, sessionID, payload.SessionID)
+	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+}
+
+func TestExpiredPasetoV4Token(t *testing.T) {
+	key := randomEd25519Key(t, "key-1", time.Now().Add(-time.Hour))
+	maker, err := NewPasetoV4Maker([]Ed25519Key{key}, key.ID)
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), -time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestInvalidPasetoV4Token(t *testing.T) {
+	key := randomEd25519Key(t, "key-1", time.Now().Add(-time.Hour))
+	maker, err := NewPasetoV4Maker([]Ed25519Key{key}, key.ID)
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute)
+	require.NoError(t, err)
+
+	// a token signed by an unknown key with the same key id
+	otherKey := randomEd25519Key(t, "key-1", time.Now().Add(-time.Hour))
+	otherMaker, err := NewPasetoV4Maker([]Ed25519Key{otherKey}, otherKey.ID)
+	require.NoError(t, err)
+
+	payload, err := otherMaker.VerifyToken(token)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	// a token whose body has been tampered with
+	parts := strings.Split(token, ".")
+	body := []byte(parts[2])
+	body[0] ^= 1
+	parts[2] = string(body)
+
+	payload, err = maker.VerifyToken(strings.Join(parts, "."))
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	// a symmetric token
+	symmetricMaker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+	symmetricToken, _, err := symmetricMaker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute)
+	require.NoError(t, err)
+
+	payload, err = maker.VerifyToken(symmetricToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoV4KeyRotation(t *testing.T) {
+	oldKey := randomEd25519Key(t, "key-1", time.Now().Add(-time.Hour))
+	oldMaker, err := NewPasetoV4Maker([]Ed25519Key{oldKey}, oldKey.ID)
+	require.NoError(t, err)
+
+	oldToken, _, err := oldMaker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute)
+	require.NoError(t, err)
+
+	// the new key is scheduled to take over from the current one
+	newKey := randomEd25519Key(t, "key-2", time.Now().Add(-time.Minute))
+	maker, err := NewPasetoV4Maker([]Ed25519Key{oldKey, newKey}, oldKey.ID)
+	require.NoError(t, err)
+
+	newToken, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(newToken, ".eyJraWQiOiJrZXktMiJ9")) // {"kid":"key-2"}
+
+	// tokens signed by the previous key stay valid
+	_, err = maker.VerifyToken(oldToken)
+	require.NoError(t, err)
+
+	// a key scheduled in the future is not used yet
+	futureKey := randomEd25519Key(t, "key-3", time.Now().Add(time.Hour))
+	maker, err = NewPasetoV4Maker([]Ed25519Key{oldKey, futureKey}, oldKey.ID)
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(token, ".eyJraWQiOiJrZXktMSJ9")) // {"kid":"key-1"}
+
+	// once the previous key is retired its tokens are rejected
+	retiredKey := Ed25519Key{ID: oldKey.ID, PublicKey: oldKey.PrivateKey.Public().(ed25519.PublicKey), NotAfter: time.Now().Add(-time.Second)}
+	maker, err = NewPasetoV4Maker([]Ed25519Key{retiredKey, newKey}, newKey.ID)
+	require.NoError(t, err)
+
+	_, err = maker.VerifyToken(oldToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+}
+
+func TestNewPasetoV4MakerInvalidKeys(t *testing.T) {
+	key := randomEd25519Key(t, "key-1", time.Now())
+
+	_, err := NewPasetoV4Maker([]Ed25519Key{key, key}, key.ID)
+	require.Error(t, err)
+
+	_, err = NewPasetoV4Maker([]Ed25519Key{key}, "unknown")
+	require.Error(t, err)
+
+	verifyOnly := Ed25519Key{ID: "key-2", PublicKey: key.PrivateKey.Public().(ed25519.PublicKey)}
+	_, err = NewPasetoV4Maker([]Ed25519Key{verifyOnly}, verifyOnly.ID)
+	require.Error(t, err)
+}
+
+// TestPasetoV4PublicTestVector checks the signature against the official PASETO test vector 4-S-2
+func TestPasetoV4PublicTestVector(t *testing.T) {
+	publicKey, err := hex.DecodeString("1eb9dbbbbc047c03fd70604e0071f0987e16b28b757225c11f00415d0e20b1a2")
+	require.NoError(t, err)
+
+	maker, err := NewPasetoV4Maker([]Ed25519Key{{
+		ID:        "zVhMiPBP9fRf2snEcT7gFTioeA9COcNy9DfgL1W60haN",
+		PublicKey: publicKey,
+	}}, "")
+	require.NoError(t, err)
+
+	token := "v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9v3Jt8mx_TdM2ceTGoqwrh4yDFn0XsHvvV_D0DtwQxVrJEBMl0F2caAdgnpKlt4p7xBnx1HcO-SPo8FPp214HDw.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9"
+
+	// the signature is accepted, the payload is then rejected because it has no expires_at claim
+	_, err = maker.VerifyToken(token)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+}
+
+func TestNewMakerFromKeyFile(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+
+	keyFile := filepath.Join(t.TempDir(), "token_keys.json")
+	content := fmt.Sprintf(`[{"kid":"key-1","private_key":%q,"not_before":"2020-01-01T00:00:00Z"}]`, base64.StdEncoding.EncodeToString(seed))
+	require.NoError(t, os.WriteFile(keyFile, []byte(content), 0o600))
+
+	maker, err := NewMaker(util.Config{TokenKeysFile: keyFile, TokenSigningKeyID: "key-1"})
+	require.NoError(t, err)
+	require.IsType(t, &PasetoV4Maker{}, maker)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.DepositorRole, uuid.New(), time.Minute)
+	require.NoError(t, err)
+
+	_, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+
+	// without a key file the symmetric maker is used
+	maker, err = NewMaker(util.Config{TokenSymmetricKey: util.RandomString(32)})
+	require.NoError(t, err)
+	require.IsType(t, &PasetoMaker{}, maker)
+}
@@ -19,12 +19,13 @@ type Payload struct {
 	ID        uuid.UUID `json:"id"`
 	Username  string    `json:"username"`
 	Role      util.Role `json:"role"`
+	SessionID uuid.UUID `json:"session_id"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expires_at"`
 }
 
-// NewPayload creates a new token payload with a specific username and duration
-func NewPayload(username string, role util.Role, duration time.Duration) (*Payload, error) {
+// NewPayload creates a new token payload with a specific username, session and duration
+func NewPayload(username string, role util.Role, sessionID uuid.UUID, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -34,6 +35,7 @@ func NewPayload(username string, role util.Role, duration time.Duration) (*Paylo
 		ID:        tokenID,
 		Username:  username,
 		Role:      role,
+		SessionID: sessionID,
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(duration),
 	}
@@ -10,19 +10,32 @@ import (
 // Config stores all configuration of the application.
 // The values are read by viper from a config file or environment variables.
 type Config struct {
-	Environment          string        `mapstructure:"ENV"`
-	DBDriver             string        `mapstructure:"DB_DRIVER"`
-	DBSource             string        `mapstructure:"DB_SOURCE"`
-	MigrationsURL        string        `mapstructure:"MIGRATION_URL"`
-	HTTPServerAddress    string        `mapstructure:"HTTP_SERVER_ADDRESS"`
-	GRPCServerAddress    string        `mapstructure:"GRPC_SERVER_ADDRESS"`
-	RedisAddress         string        `mapstructure:"REDIS_ADDRESS"`
-	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
-	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
-	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
-	EmailSenderName      string        `mapstructure:"EMAIL_SENDER_NAME"`
-	EmailSenderAddress   string        `mapstructure:"EMAIL_SENDER_ADDRESS"`
-	EmailSenderPassword  string        `mapstructure:"EMAIL_SENDER_PASSWORD"`
+	Environment                 string        `mapstructure:"ENV"`
+	DBDriver                    string        `mapstructure:"DB_DRIVER"`
+	DBSource                    string        `mapstructure:"DB_SOURCE"`
+	MigrationsURL               string        `mapstructure:"MIGRATION_URL"`
+	HTTPServerAddress           string        `mapstructure:"HTTP_SERVER_ADDRESS"`
+	GRPCServerAddress           string        `mapstructure:"GRPC_SERVER_ADDRESS"`
+	AdminServerAddress          string        `mapstructure:"ADMIN_SERVER_ADDRESS"`
+	RedisAddress                string        `mapstructure:"REDIS_ADDRESS"`
+	TokenSymmetricKey           string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	TokenKeysFile               string        `mapstructure:"TOKEN_KEYS_FILE"`
+	TokenSigningKeyID           string        `mapstructure:"TOKEN_SIGNING_KEY_ID"`
+	AccessTokenDuration         time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration        time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	EmailSenderName             string        `mapstructure:"EMAIL_SENDER_NAME"`
+	EmailSenderAddress          string        `mapstructure:"EMAIL_SENDER_ADDRESS"`
+	EmailSenderPassword         string        `mapstructure:"EMAIL_SENDER_PASSWORD"`
+	Currencies                  string        `mapstructure:"CURRENCIES"`
+	CurrencyRegistry            Currencies    `mapstructure:"-"`
+	StatementDir                string        `mapstructure:"STATEMENT_DIR"`
+	StatementSchedule           string        `mapstructure:"STATEMENT_SCHEDULE"`
+	SessionCleanupSchedule      string        `mapstructure:"SESSION_CLEANUP_SCHEDULE"`
+	VerifyEmailPurgeSchedule    string        `mapstructure:"VERIFY_EMAIL_PURGE_SCHEDULE"`
+	OutboxPurgeSchedule         string        `mapstructure:"OUTBOX_PURGE_SCHEDULE"`
+	PasswordResetPurgeSchedule  string        `mapstructure:"PASSWORD_RESET_PURGE_SCHEDULE"`
+	IdempotencyKeyPurgeSchedule string        `mapstructure:"IDEMPOTENCY_KEY_PURGE_SCHEDULE"`
+	OutboxRelayInterval         time.Duration `mapstructure:"OUTBOX_RELAY_INTERVAL"`
 }
 
 // LoadConfig reads configuration from file or environment variables.
@@ -45,5 +58,16 @@ func LoadConfig(path string) (Config, error) {
 		return Config{}, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	// The currency registry is carried by the config instead of a package global
+	spec := config.Currencies
+	if spec == "" {
+		spec = DefaultCurrencies
+	}
+	registry, err := ParseCurrencies(spec)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to parse currencies: %w", err)
+	}
+	config.CurrencyRegistry = registry
+
 	return config, nil
 }
@@ -1,5 +1,13 @@
 package util
 
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
 // Constants for all support currencies
 const (
 	USD = "USD"
@@ -7,11 +15,164 @@ const (
 	THB = "THB"
 )
 
-// IsSupportedCurrency returns true if the currency is supported
-func IsSupportedCurrency(currecy string) bool {
-	switch currecy {
-	case USD, EUR, THB:
-		return true
+// Currency describes an ISO 4217 currency that the bank can hold balances in.
+// Amounts are always stored as integers in the currency's minor unit.
+type Currency struct {
+	Code      string
+	Numeric   int
+	MinorUnit int
+	Symbol    string
+}
+
+// DefaultCurrencies is the registry used when no CURRENCIES config is provided
+const DefaultCurrencies = "USD:840:2:$,EUR:978:2:€,THB:764:2:฿"
+
+// Currencies is a registry of supported currencies keyed by code.
+// It is built once from the config and only read afterwards.
+type Currencies map[string]Currency
+
+// ParseCurrencies parses a comma separated list of CODE:NUMERIC:MINOR_UNIT:SYMBOL entries
+func ParseCurrencies(spec string) (Currencies, error) {
+	registry := make(Currencies)
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		fields := strings.SplitN(item, ":", 4)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("invalid currency %q: expected CODE:NUMERIC:MINOR_UNIT:SYMBOL", item)
+		}
+
+		code := strings.ToUpper(strings.TrimSpace(fields[0]))
+		if len(code) != 3 {
+			return nil, fmt.Errorf("invalid currency %q: code must have 3 letters", item)
+		}
+
+		numeric, err := strconv.Atoi(fields[1])
+		if err != nil || numeric <= 0 || numeric > 999 {
+			return nil, fmt.Errorf("invalid currency %q: numeric code must be between 1 and 999", item)
+		}
+
+		minorUnit, err := strconv.Atoi(fields[2])
+		if err != nil || minorUnit < 0 || minorUnit > 4 {
+			return nil, fmt.Errorf("invalid currency %q: minor unit must be between 0 and 4", item)
+		}
+
+		if _, ok := registry[code]; ok {
+			return nil, fmt.Errorf("duplicate currency %s", code)
+		}
+
+		registry[code] = Currency{
+			Code:      code,
+			Numeric:   numeric,
+			MinorUnit: minorUnit,
+			Symbol:    fields[3],
+		}
 	}
-	return false
+
+	if len(registry) == 0 {
+		return nil, fmt.Errorf("no currency configured")
+	}
+
+	return registry, nil
+}
+
+// NewDefaultCurrencies returns the registry of DefaultCurrencies
+func NewDefaultCurrencies() Currencies {
+	registry, err := ParseCurrencies(DefaultCurrencies)
+	if err != nil {
+		panic(err)
+	}
+	return registry
+}
+
+// Lookup returns the registry entry for a currency code
+func (currencies Currencies) Lookup(code string) (Currency, bool) {
+	currency, ok := currencies[code]
+	return currency, ok
+}
+
+// IsSupported returns true if the currency is supported
+func (currencies Currencies) IsSupported(code string) bool {
+	_, ok := currencies[code]
+	return ok
+}
+
+// Validate returns an error if the currency is not supported
+func (currencies Currencies) Validate(code string) error {
+	if !currencies.IsSupported(code) {
+		return fmt.Errorf("unsupported currency")
+	}
+	return nil
+}
+
+// Codes returns the codes of all supported currencies in alphabetical order
+func (currencies Currencies) Codes() []string {
+	codes := make([]string, 0, len(currencies))
+	for code := range currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// MinorUnit returns the number of decimal places used by a currency.
+// Unknown currencies are treated as having 2 decimal places.
+func (currencies Currencies) MinorUnit(code string) int {
+	if currency, ok := currencies[code]; ok {
+		return currency.MinorUnit
+	}
+	return 2
+}
+
+// FormatAmount renders an amount in minor units as a human readable string, e.g. "-$1,234.56"
+func (currencies Currencies) FormatAmount(amount int64, code string) string {
+	currency, ok := currencies[code]
+	if !ok {
+		currency = Currency{Code: code, MinorUnit: 2, Symbol: code + " "}
+	}
+
+	sign, whole, fraction := splitAmount(amount, currency.MinorUnit)
+
+	var b strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	if fraction != "" {
+		b.WriteByte('.')
+		b.WriteString(fraction)
+	}
+
+	return sign + currency.Symbol + b.String()
+}
+
+// FormatDecimal renders an amount in minor units as a plain decimal without symbol or grouping, e.g. "-1234.56"
+func FormatDecimal(amount int64, minorUnit int) string {
+	sign, whole, fraction := splitAmount(amount, minorUnit)
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction
+}
+
+// splitAmount splits an amount in minor units into its sign, whole and fractional digits
+func splitAmount(amount int64, minorUnit int) (sign string, whole string, fraction string) {
+	abs := new(big.Int).SetInt64(amount)
+	if abs.Sign() < 0 {
+		sign = "-"
+		abs.Neg(abs)
+	}
+
+	digits := abs.String()
+	if len(digits) <= minorUnit {
+		digits = strings.Repeat("0", minorUnit-len(digits)+1) + digits
+	}
+
+	return sign, digits[:len(digits)-minorUnit], digits[len(digits)-minorUnit:]
 }
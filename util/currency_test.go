@@ -0,0 +1,48 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCurrencies(t *testing.T) {
+	registry, err := ParseCurrencies("USD:840:2:$, jpy:392:0:¥")
+	require.NoError(t, err)
+	require.Len(t, registry, 2)
+	require.Equal(t, Currency{Code: "JPY", Numeric: 392, MinorUnit: 0, Symbol: "¥"}, registry["JPY"])
+
+	_, err = ParseCurrencies("USD:840:2")
+	require.Error(t, err)
+
+	_, err = ParseCurrencies("USD:840:2:$,USD:840:2:$")
+	require.Error(t, err)
+
+	_, err = ParseCurrencies("USD:abc:2:$")
+	require.Error(t, err)
+
+	_, err = ParseCurrencies("")
+	require.Error(t, err)
+}
+
+func TestFormatAmount(t *testing.T) {
+	currencies, err := ParseCurrencies("USD:840:2:$,JPY:392:0:¥,KWD:414:3:KD")
+	require.NoError(t, err)
+
+	require.True(t, currencies.IsSupported("JPY"))
+	require.False(t, currencies.IsSupported(EUR))
+	require.Error(t, currencies.Validate(EUR))
+	require.Equal(t, []string{"JPY", "KWD", USD}, currencies.Codes())
+
+	require.Equal(t, "$0.00", currencies.FormatAmount(0, USD))
+	require.Equal(t, "$0.05", currencies.FormatAmount(5, USD))
+	require.Equal(t, "$1,234.56", currencies.FormatAmount(123456, USD))
+	require.Equal(t, "-$1,234,567.89", currencies.FormatAmount(-123456789, USD))
+	require.Equal(t, "¥1,000", currencies.FormatAmount(1000, "JPY"))
+	require.Equal(t, "KD12.345", currencies.FormatAmount(12345, "KWD"))
+	require.Equal(t, "EUR 1.00", currencies.FormatAmount(100, EUR))
+
+	require.Equal(t, "-1234567.89", FormatDecimal(-123456789, currencies.MinorUnit(USD)))
+	require.Equal(t, "1000", FormatDecimal(1000, currencies.MinorUnit("JPY")))
+	require.Equal(t, "0.005", FormatDecimal(5, currencies.MinorUnit("KWD")))
+}
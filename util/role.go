@@ -5,4 +5,6 @@ type Role string
 const (
 	DepositorRole Role = "depositor"
 	BankerRole    Role = "banker"
+	// SystemRole owns internal ledger accounts and cannot log in
+	SystemRole Role = "system"
 )
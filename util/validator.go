@@ -4,11 +4,27 @@ import (
 	"fmt"
 	"net/mail"
 	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Directions of an entry, credits add money to the account and debits take it out
+const (
+	CreditDirection = "credit"
+	DebitDirection  = "debit"
+)
+
+// Directions of a transfer seen from one of its accounts
+const (
+	IncomingDirection = "incoming"
+	OutgoingDirection = "outgoing"
 )
 
 var (
 	isValidUsername = regexp.MustCompile(`^[a-z0-9_]+$`).MatchString
 	isValidFullName = regexp.MustCompile(`^[a-zA-Z\s]+$`).MatchString
+	isValidRate     = regexp.MustCompile(`^[0-9]{1,10}(\.[0-9]{1,8})?$`).MatchString
 )
 
 func ValidateString(value string, minLength int, maxLength int) error {
@@ -63,9 +79,93 @@ func ValidateEmailId(value int64) error {
 	return nil
 }
 
+func ValidateResetId(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be greater than 0")
+	}
+	return nil
+}
+
 func ValidateSecretCode(value string) error {
 	if err := ValidateString(value, 32, 32); err != nil {
 		return err
 	}
 	return nil
 }
+
+func ValidateAccountId(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be greater than 0")
+	}
+	return nil
+}
+
+func ValidateTransferId(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be greater than 0")
+	}
+	return nil
+}
+
+func ValidatePageID(value int32) error {
+	if value < 1 {
+		return fmt.Errorf("must be greater than 0")
+	}
+	return nil
+}
+
+func ValidatePageSize(value int32) error {
+	if value < 1 || value > 100 {
+		return fmt.Errorf("must be between 1 and 100")
+	}
+	return nil
+}
+
+func ValidateAmount(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be greater than 0")
+	}
+	return nil
+}
+
+func ValidateEntryDirection(value string) error {
+	if value != CreditDirection && value != DebitDirection {
+		return fmt.Errorf("must be %s or %s", CreditDirection, DebitDirection)
+	}
+	return nil
+}
+
+func ValidateTransferDirection(value string) error {
+	if value != IncomingDirection && value != OutgoingDirection {
+		return fmt.Errorf("must be %s or %s", IncomingDirection, OutgoingDirection)
+	}
+	return nil
+}
+
+func ValidateIdempotencyKey(value string) error {
+	return ValidateString(value, 1, 255)
+}
+
+func ValidateSessionID(value string) error {
+	if _, err := uuid.Parse(value); err != nil {
+		return fmt.Errorf("must be a valid UUID")
+	}
+	return nil
+}
+
+func ValidateQuoteID(value string) error {
+	if _, err := uuid.Parse(value); err != nil {
+		return fmt.Errorf("must be a valid UUID")
+	}
+	return nil
+}
+
+func ValidateExchangeRate(value string) error {
+	if !isValidRate(value) {
+		return fmt.Errorf("must be a decimal number with at most 10 integer and 8 fractional digits")
+	}
+	if strings.Trim(value, "0.") == "" {
+		return fmt.Errorf("must be greater than 0")
+	}
+	return nil
+}
@@ -3,11 +3,13 @@ package worker
 import (
 	"context"
 
+	db "github.com/guncv/Simple-Bank/db/sqlc"
 	"github.com/hibiken/asynq"
 )
 
 type TaskDistributor interface {
-	DistributeTaskSendVerifyEmail(ctx context.Context, payload *PayloadSendVerifyEmail, opts ...asynq.Option) error
+	DistributeTaskSendStatement(ctx context.Context, payload *PayloadSendStatement, opts ...asynq.Option) error
+	DistributeOutboxMessage(ctx context.Context, message db.Outbox) error
 }
 
 type RedisTaskDistributor struct {
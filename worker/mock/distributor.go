@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	sqlc "github.com/guncv/Simple-Bank/db/sqlc"
 	worker "github.com/guncv/Simple-Bank/worker"
 	asynq "github.com/hibiken/asynq"
 )
@@ -36,21 +37,35 @@ func (m *MockTaskDistributor) EXPECT() *MockTaskDistributorMockRecorder {
 	return m.recorder
 }
 
-// DistributeTaskSendVerifyEmail mocks base method.
-func (m *MockTaskDistributor) DistributeTaskSendVerifyEmail(arg0 context.Context, arg1 *worker.PayloadSendVerifyEmail, arg2 ...asynq.Option) error {
+// DistributeOutboxMessage mocks base method.
+func (m *MockTaskDistributor) DistributeOutboxMessage(arg0 context.Context, arg1 sqlc.Outbox) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DistributeOutboxMessage", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DistributeOutboxMessage indicates an expected call of DistributeOutboxMessage.
+func (mr *MockTaskDistributorMockRecorder) DistributeOutboxMessage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeOutboxMessage", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeOutboxMessage), arg0, arg1)
+}
+
+// DistributeTaskSendStatement mocks base method.
+func (m *MockTaskDistributor) DistributeTaskSendStatement(arg0 context.Context, arg1 *worker.PayloadSendStatement, arg2 ...asynq.Option) error {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{arg0, arg1}
 	for _, a := range arg2 {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "DistributeTaskSendVerifyEmail", varargs...)
+	ret := m.ctrl.Call(m, "DistributeTaskSendStatement", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DistributeTaskSendVerifyEmail indicates an expected call of DistributeTaskSendVerifyEmail.
-func (mr *MockTaskDistributorMockRecorder) DistributeTaskSendVerifyEmail(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+// DistributeTaskSendStatement indicates an expected call of DistributeTaskSendStatement.
+func (mr *MockTaskDistributorMockRecorder) DistributeTaskSendStatement(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	varargs := append([]interface{}{arg0, arg1}, arg2...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTaskSendVerifyEmail", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeTaskSendVerifyEmail), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTaskSendStatement", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeTaskSendStatement), varargs...)
 }
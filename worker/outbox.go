@@ -0,0 +1,153 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"time"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	outboxRelayBatchSize       = 100
+	defaultOutboxRelayInterval = time.Second
+	outboxPublishTimeout       = 5 * time.Second
+)
+
+// Outbox metrics are published under "outbox" on /debug/vars of the admin server
+var (
+	outboxPending    = new(expvar.Int)
+	outboxLagSeconds = new(expvar.Float)
+	outboxSent       = new(expvar.Int)
+	outboxFailed     = new(expvar.Int)
+)
+
+func init() {
+	metrics := expvar.NewMap("outbox")
+	metrics.Set("pending", outboxPending)
+	metrics.Set("lag_seconds", outboxLagSeconds)
+	metrics.Set("sent", outboxSent)
+	metrics.Set("failed", outboxFailed)
+}
+
+// NewOutboxMessage builds an outbox message that queues the task once the surrounding transaction commits
+func NewOutboxMessage(taskType string, payload any, queue string, maxRetry int32) (db.CreateOutboxMessageParams, error) {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return db.CreateOutboxMessageParams{}, fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	return db.CreateOutboxMessageParams{
+		TaskType: taskType,
+		Payload:  jsonPayload,
+		Queue:    queue,
+		MaxRetry: maxRetry,
+	}, nil
+}
+
+func (distributor *RedisTaskDistributor) DistributeOutboxMessage(ctx context.Context, message db.Outbox) error {
+	task := asynq.NewTask(message.TaskType, message.Payload,
+		asynq.Queue(message.Queue),
+		asynq.MaxRetry(int(message.MaxRetry)),
+		// a message relayed again after a failed commit does not duplicate a task that is still queued
+		asynq.TaskID(fmt.Sprintf("outbox:%d", message.ID)),
+	)
+
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", info.Type).
+		Str("queue", info.Queue).
+		Int("max_retry", info.MaxRetry).
+		Int64("outbox_id", message.ID).
+		Msg("enqueued task")
+	return nil
+}
+
+// OutboxRelay publishes the messages written to the outbox through the task distributor
+type OutboxRelay struct {
+	store       db.Store
+	distributor TaskDistributor
+	interval    time.Duration
+}
+
+func NewOutboxRelay(store db.Store, distributor TaskDistributor, interval time.Duration) *OutboxRelay {
+	if interval <= 0 {
+		interval = defaultOutboxRelayInterval
+	}
+
+	return &OutboxRelay{
+		store:       store,
+		distributor: distributor,
+		interval:    interval,
+	}
+}
+
+// Start polls the outbox every interval until ctx is done
+func (relay *OutboxRelay) Start(ctx context.Context) {
+	ticker := time.NewTicker(relay.interval)
+	defer ticker.Stop()
+
+	for {
+		relay.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain relays batches until the outbox has no pending message left
+func (relay *OutboxRelay) drain(ctx context.Context) {
+	for {
+		result, err := relay.store.RelayOutboxTx(ctx, db.RelayOutboxTxParams{
+			Limit:          outboxRelayBatchSize,
+			PublishTimeout: outboxPublishTimeout,
+			Publish:        relay.publish,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("failed to relay outbox")
+			break
+		}
+
+		outboxSent.Add(int64(len(result.Sent)))
+		outboxFailed.Add(int64(len(result.Failed)))
+		for _, message := range result.Failed {
+			log.Error().Int64("outbox_id", message.ID).
+				Str("type", message.TaskType).
+				Int32("attempts", message.Attempts+1).
+				Msg("failed to publish outbox message")
+		}
+
+		if len(result.Sent)+len(result.Failed) < outboxRelayBatchSize {
+			break
+		}
+	}
+
+	stats, err := relay.store.GetOutboxStats(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get outbox stats")
+		return
+	}
+	outboxPending.Set(stats.Pending)
+	outboxLagSeconds.Set(stats.LagSeconds)
+}
+
+// publish enqueues a single message. A task ID conflict means the message was already enqueued by a
+// relay whose transaction failed to commit, so it counts as sent.
+func (relay *OutboxRelay) publish(ctx context.Context, message db.Outbox) error {
+	err := relay.distributor.DistributeOutboxMessage(ctx, message)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
+	return err
+}
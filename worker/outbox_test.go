@@ -0,0 +1,165 @@
+package worker_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/worker"
+	mockworker "github.com/guncv/Simple-Bank/worker/mock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+// relayOutbox plays the part of RelayOutboxTx for a batch of pending messages
+func relayOutbox(messages []db.Outbox) func(context.Context, db.RelayOutboxTxParams) (db.RelayOutboxTxResult, error) {
+	return func(ctx context.Context, arg db.RelayOutboxTxParams) (db.RelayOutboxTxResult, error) {
+		var result db.RelayOutboxTxResult
+		for _, message := range messages {
+			if err := arg.Publish(ctx, message); err != nil {
+				result.Failed = append(result.Failed, message)
+				continue
+			}
+			result.Sent = append(result.Sent, message)
+		}
+		return result, nil
+	}
+}
+
+func TestOutboxRelayPublish(t *testing.T) {
+	message := db.Outbox{ID: 1, TaskType: worker.TaskSendVerifyEmail, Queue: worker.QueueCritical, MaxRetry: 10}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(distributor *mockworker.MockTaskDistributor)
+		checkResponse func(t *testing.T, result db.RelayOutboxTxResult)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(distributor *mockworker.MockTaskDistributor) {
+				distributor.EXPECT().DistributeOutboxMessage(gomock.Any(), gomock.Eq(message)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, result db.RelayOutboxTxResult) {
+				require.Equal(t, []db.Outbox{message}, result.Sent)
+				require.Empty(t, result.Failed)
+			},
+		},
+		{
+			name: "TaskIDConflict",
+			buildStubs: func(distributor *mockworker.MockTaskDistributor) {
+				// an earlier relay enqueued the task but failed to mark the message sent
+				distributor.EXPECT().DistributeOutboxMessage(gomock.Any(), gomock.Eq(message)).Times(1).
+					Return(fmt.Errorf("failed to enqueue task: %w", asynq.ErrTaskIDConflict))
+			},
+			checkResponse: func(t *testing.T, result db.RelayOutboxTxResult) {
+				require.Equal(t, []db.Outbox{message}, result.Sent)
+				require.Empty(t, result.Failed)
+			},
+		},
+		{
+			name: "EnqueueFailed",
+			buildStubs: func(distributor *mockworker.MockTaskDistributor) {
+				distributor.EXPECT().DistributeOutboxMessage(gomock.Any(), gomock.Eq(message)).Times(1).
+					Return(errors.New("redis unavailable"))
+			},
+			checkResponse: func(t *testing.T, result db.RelayOutboxTxResult) {
+				require.Empty(t, result.Sent)
+				require.Equal(t, []db.Outbox{message}, result.Failed)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			distributor := mockworker.NewMockTaskDistributor(ctrl)
+			tc.buildStubs(distributor)
+
+			var result db.RelayOutboxTxResult
+			store.EXPECT().RelayOutboxTx(gomock.Any(), gomock.Any()).Times(1).
+				DoAndReturn(func(ctx context.Context, arg db.RelayOutboxTxParams) (db.RelayOutboxTxResult, error) {
+					require.Positive(t, arg.PublishTimeout)
+
+					var err error
+					result, err = relayOutbox([]db.Outbox{message})(ctx, arg)
+					return result, err
+				})
+			store.EXPECT().GetOutboxStats(gomock.Any()).Times(1).Return(db.GetOutboxStatsRow{}, nil)
+
+			runOutboxRelay(t, worker.NewOutboxRelay(store, distributor, time.Hour))
+			tc.checkResponse(t, result)
+		})
+	}
+}
+
+func TestOutboxRelayRetryAfterBackoff(t *testing.T) {
+	message := db.Outbox{ID: 1, TaskType: worker.TaskSendVerifyEmail, Queue: worker.QueueCritical, MaxRetry: 10}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	distributor := mockworker.NewMockTaskDistributor(ctrl)
+
+	// the failed message is not published again until the store hands it out after its backoff
+	gomock.InOrder(
+		distributor.EXPECT().DistributeOutboxMessage(gomock.Any(), gomock.Eq(message)).Times(1).
+			Return(errors.New("redis unavailable")),
+		distributor.EXPECT().DistributeOutboxMessage(gomock.Any(), gomock.Eq(message)).Times(1).
+			Return(nil),
+	)
+
+	var results []db.RelayOutboxTxResult
+	record := func(messages []db.Outbox) func(context.Context, db.RelayOutboxTxParams) (db.RelayOutboxTxResult, error) {
+		return func(ctx context.Context, arg db.RelayOutboxTxParams) (db.RelayOutboxTxResult, error) {
+			result, err := relayOutbox(messages)(ctx, arg)
+			results = append(results, result)
+			return result, err
+		}
+	}
+	gomock.InOrder(
+		store.EXPECT().RelayOutboxTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(record([]db.Outbox{message})),
+		store.EXPECT().RelayOutboxTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(record(nil)),
+		store.EXPECT().RelayOutboxTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(record([]db.Outbox{message})),
+	)
+	store.EXPECT().GetOutboxStats(gomock.Any()).Times(3).Return(db.GetOutboxStatsRow{}, nil)
+
+	relay := worker.NewOutboxRelay(store, distributor, time.Hour)
+	for i := 0; i < 3; i++ {
+		runOutboxRelay(t, relay)
+	}
+
+	require.Len(t, results, 3)
+	require.Equal(t, []db.Outbox{message}, results[0].Failed)
+	require.Empty(t, results[1].Sent)
+	require.Empty(t, results[1].Failed)
+	require.Equal(t, []db.Outbox{message}, results[2].Sent)
+}
+
+// runOutboxRelay drains the outbox once and stops the relay
+func runOutboxRelay(t *testing.T, relay *worker.OutboxRelay) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		relay.Start(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("outbox relay did not stop")
+	}
+}
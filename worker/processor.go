@@ -5,6 +5,7 @@ import (
 
 	db "github.com/guncv/Simple-Bank/db/sqlc"
 	"github.com/guncv/Simple-Bank/mail"
+	"github.com/guncv/Simple-Bank/util"
 	"github.com/hibiken/asynq"
 	"github.com/rs/zerolog/log"
 )
@@ -17,15 +18,34 @@ const (
 type TaskProcessor interface {
 	Start() error
 	ProcessTaskSendVerifyEmail(ctx context.Context, task *asynq.Task) error
+	ProcessTaskRequestPasswordReset(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendPasswordReset(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendStatement(ctx context.Context, task *asynq.Task) error
+	ProcessTaskEnqueueStatements(ctx context.Context, task *asynq.Task) error
+	ProcessTaskCleanupSessions(ctx context.Context, task *asynq.Task) error
+	ProcessTaskPurgeVerifyEmails(ctx context.Context, task *asynq.Task) error
+	ProcessTaskPurgeOutbox(ctx context.Context, task *asynq.Task) error
+	ProcessTaskPurgePasswordResets(ctx context.Context, task *asynq.Task) error
+	ProcessTaskPurgeIdempotencyKeys(ctx context.Context, task *asynq.Task) error
 }
 
 type RedisTaskProcessor struct {
-	server *asynq.Server
-	store  db.Store
-	mailer mail.EmailSender
+	server       *asynq.Server
+	store        db.Store
+	mailer       mail.EmailSender
+	distributor  TaskDistributor
+	statementDir string
+	currencies   util.Currencies
 }
 
-func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, mailer mail.EmailSender) TaskProcessor {
+func NewRedisTaskProcessor(
+	redisOpt asynq.RedisClientOpt,
+	store db.Store,
+	mailer mail.EmailSender,
+	distributor TaskDistributor,
+	statementDir string,
+	currencies util.Currencies,
+) TaskProcessor {
 	server := asynq.NewServer(redisOpt, asynq.Config{
 		Concurrency: 10,
 		Queues: map[string]int{
@@ -43,15 +63,27 @@ func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, mailer
 	})
 
 	return &RedisTaskProcessor{
-		server: server,
-		store:  store,
-		mailer: mailer,
+		server:       server,
+		store:        store,
+		mailer:       mailer,
+		distributor:  distributor,
+		statementDir: statementDir,
+		currencies:   currencies,
 	}
 }
 
 func (processor *RedisTaskProcessor) Start() error {
 	mux := asynq.NewServeMux()
 	mux.HandleFunc(TaskSendVerifyEmail, processor.ProcessTaskSendVerifyEmail)
+	mux.HandleFunc(TaskRequestPasswordReset, processor.ProcessTaskRequestPasswordReset)
+	mux.HandleFunc(TaskSendPasswordReset, processor.ProcessTaskSendPasswordReset)
+	mux.HandleFunc(TaskSendStatement, processor.ProcessTaskSendStatement)
+	mux.HandleFunc(TaskEnqueueStatements, processor.ProcessTaskEnqueueStatements)
+	mux.HandleFunc(TaskCleanupSessions, processor.ProcessTaskCleanupSessions)
+	mux.HandleFunc(TaskPurgeVerifyEmails, processor.ProcessTaskPurgeVerifyEmails)
+	mux.HandleFunc(TaskPurgeOutbox, processor.ProcessTaskPurgeOutbox)
+	mux.HandleFunc(TaskPurgePasswordResets, processor.ProcessTaskPurgePasswordResets)
+	mux.HandleFunc(TaskPurgeIdempotencyKeys, processor.ProcessTaskPurgeIdempotencyKeys)
 
 	if err := processor.server.Start(mux); err != nil {
 		log.Error().Err(err).Msg("failed to start server")
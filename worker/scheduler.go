@@ -0,0 +1,115 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/hibiken/asynq"
+	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog/log"
+)
+
+// periodicTaskRetention keeps a finished periodic task around under the ID of its tick, so that the other
+// replicas firing the same cron tick get a task ID conflict instead of running the job again
+const periodicTaskRetention = time.Hour
+
+// PeriodicTask runs a task without payload on a cron schedule
+type PeriodicTask struct {
+	CronSpec string
+	TaskType string
+}
+
+// PeriodicTasks returns the recurring jobs configured in config, a job with an empty schedule is disabled
+func PeriodicTasks(config util.Config) []PeriodicTask {
+	tasks := []PeriodicTask{
+		{CronSpec: config.StatementSchedule, TaskType: TaskEnqueueStatements},
+		{CronSpec: config.SessionCleanupSchedule, TaskType: TaskCleanupSessions},
+		{CronSpec: config.VerifyEmailPurgeSchedule, TaskType: TaskPurgeVerifyEmails},
+		{CronSpec: config.OutboxPurgeSchedule, TaskType: TaskPurgeOutbox},
+		{CronSpec: config.PasswordResetPurgeSchedule, TaskType: TaskPurgePasswordResets},
+		{CronSpec: config.IdempotencyKeyPurgeSchedule, TaskType: TaskPurgeIdempotencyKeys},
+	}
+
+	enabled := tasks[:0]
+	for _, task := range tasks {
+		if task.CronSpec != "" {
+			enabled = append(enabled, task)
+		}
+	}
+	return enabled
+}
+
+type TaskScheduler interface {
+	Start() error
+}
+
+// RedisTaskScheduler fires the periodic tasks itself rather than through asynq.Scheduler, whose options are
+// fixed at registration, so that every tick is enqueued under its own task ID
+type RedisTaskScheduler struct {
+	client *asynq.Client
+	cron   *cron.Cron
+	tasks  []PeriodicTask
+}
+
+func NewRedisTaskScheduler(redisOpt asynq.RedisClientOpt, tasks []PeriodicTask) TaskScheduler {
+	return &RedisTaskScheduler{
+		client: asynq.NewClient(redisOpt),
+		cron:   cron.New(cron.WithLocation(time.UTC)),
+		tasks:  tasks,
+	}
+}
+
+func (scheduler *RedisTaskScheduler) Start() error {
+	for _, periodicTask := range scheduler.tasks {
+		periodicTask := periodicTask
+		entryID, err := scheduler.cron.AddFunc(periodicTask.CronSpec, func() {
+			scheduler.enqueue(periodicTask, time.Now())
+		})
+		if err != nil {
+			log.Error().Err(err).Str("type", periodicTask.TaskType).Msg("failed to register periodic task")
+			return err
+		}
+
+		log.Info().
+			Int("entry_id", int(entryID)).
+			Str("type", periodicTask.TaskType).
+			Str("cron", periodicTask.CronSpec).
+			Msg("registered periodic task")
+	}
+
+	scheduler.cron.Start()
+
+	log.Info().Msg("started scheduler")
+	return nil
+}
+
+func (scheduler *RedisTaskScheduler) enqueue(periodicTask PeriodicTask, firedAt time.Time) {
+	taskID := periodicTaskID(periodicTask.TaskType, firedAt)
+	task := asynq.NewTask(periodicTask.TaskType, []byte("{}"))
+
+	info, err := scheduler.client.Enqueue(task,
+		asynq.TaskID(taskID),
+		asynq.Retention(periodicTaskRetention),
+		asynq.Queue(QueueDefault),
+	)
+	if err != nil {
+		// another replica already enqueued this tick
+		if errors.Is(err, asynq.ErrTaskIDConflict) {
+			log.Debug().Str("type", periodicTask.TaskType).Str("task_id", taskID).Msg("periodic task already enqueued")
+			return
+		}
+		log.Error().Err(err).Str("type", periodicTask.TaskType).Msg("failed to enqueue periodic task")
+		return
+	}
+
+	log.Info().Str("type", info.Type).Str("task_id", info.ID).Msg("enqueued periodic task")
+}
+
+// periodicTaskID identifies one tick of a periodic task. Cron schedules have a one minute resolution, so every
+// replica firing the same tick derives the same ID, while a run that is still retrying or archived does not
+// block the next tick.
+func periodicTaskID(taskType string, firedAt time.Time) string {
+	return fmt.Sprintf("periodic:%s:%s", taskType, firedAt.UTC().Truncate(time.Minute).Format(time.RFC3339))
+}
@@ -0,0 +1,37 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodicTaskID(t *testing.T) {
+	tick := time.Date(2024, time.March, 1, 4, 0, 0, 0, time.UTC)
+
+	// replicas firing the same tick a few seconds apart share the ID
+	id := periodicTaskID(TaskPurgeOutbox, tick)
+	require.Equal(t, "periodic:task:purge_outbox:2024-03-01T04:00:00Z", id)
+	require.Equal(t, id, periodicTaskID(TaskPurgeOutbox, tick.Add(3*time.Second)))
+	require.Equal(t, id, periodicTaskID(TaskPurgeOutbox, tick.In(time.FixedZone("ICT", 7*60*60))))
+
+	// the next tick and other tasks get their own IDs
+	require.NotEqual(t, id, periodicTaskID(TaskPurgeOutbox, tick.Add(time.Minute)))
+	require.NotEqual(t, id, periodicTaskID(TaskCleanupSessions, tick))
+}
+
+func TestPeriodicTasks(t *testing.T) {
+	config := util.Config{
+		SessionCleanupSchedule:      "0 * * * *",
+		IdempotencyKeyPurgeSchedule: "15 4 * * *",
+	}
+
+	// jobs without a schedule are left out
+	tasks := PeriodicTasks(config)
+	require.Equal(t, []PeriodicTask{
+		{CronSpec: "0 * * * *", TaskType: TaskCleanupSessions},
+		{CronSpec: "15 4 * * *", TaskType: TaskPurgeIdempotencyKeys},
+	}, tasks)
+}
@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	TaskCleanupSessions      = "task:cleanup_sessions"
+	TaskPurgeVerifyEmails    = "task:purge_verify_emails"
+	TaskPurgeOutbox          = "task:purge_outbox"
+	TaskPurgePasswordResets  = "task:purge_password_resets"
+	TaskPurgeIdempotencyKeys = "task:purge_idempotency_keys"
+)
+
+// Expired rows are kept for a while so that recent logins and verification attempts can still be investigated
+const (
+	sessionRetention       = 7 * 24 * time.Hour
+	verifyEmailRetention   = 24 * time.Hour
+	outboxRetention        = 7 * 24 * time.Hour
+	passwordResetRetention = 24 * time.Hour
+	// idempotencyKeyRetention is how long a client can retry a transfer and get the original result back
+	idempotencyKeyRetention = 7 * 24 * time.Hour
+)
+
+func (processor *RedisTaskProcessor) ProcessTaskCleanupSessions(ctx context.Context, task *asynq.Task) error {
+	deleted, err := processor.store.DeleteExpiredSessions(ctx, time.Now().Add(-sessionRetention))
+	if err != nil {
+		return fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Int64("deleted", deleted).Msg("processed task")
+	return nil
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskPurgeVerifyEmails(ctx context.Context, task *asynq.Task) error {
+	deleted, err := processor.store.DeleteStaleVerifyEmails(ctx, time.Now().Add(-verifyEmailRetention))
+	if err != nil {
+		return fmt.Errorf("failed to delete stale verify emails: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Int64("deleted", deleted).Msg("processed task")
+	return nil
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskPurgeOutbox(ctx context.Context, task *asynq.Task) error {
+	deleted, err := processor.store.DeleteSentOutboxMessages(ctx, time.Now().Add(-outboxRetention))
+	if err != nil {
+		return fmt.Errorf("failed to delete sent outbox messages: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Int64("deleted", deleted).Msg("processed task")
+	return nil
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskPurgePasswordResets(ctx context.Context, task *asynq.Task) error {
+	deleted, err := processor.store.DeleteStalePasswordResets(ctx, time.Now().Add(-passwordResetRetention))
+	if err != nil {
+		return fmt.Errorf("failed to delete stale password resets: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Int64("deleted", deleted).Msg("processed task")
+	return nil
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskPurgeIdempotencyKeys(ctx context.Context, task *asynq.Task) error {
+	deleted, err := processor.store.DeleteStaleIdempotencyKeys(ctx, time.Now().Add(-idempotencyKeyRetention))
+	if err != nil {
+		return fmt.Errorf("failed to delete stale idempotency keys: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Int64("deleted", deleted).Msg("processed task")
+	return nil
+}
@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/statement"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskEnqueueStatements = "task:enqueue_statements"
+
+// statementTaskRetention keeps completed statement tasks, and with them their IDs, until the next month's
+// statements are due. Without retention asynq releases the ID as soon as the task completes.
+const statementTaskRetention = 32 * 24 * time.Hour
+
+type PayloadEnqueueStatements struct {
+	// PeriodStart is optional; when zero, statements are sent for the previous month
+	PeriodStart time.Time `json:"period_start"`
+}
+
+// ProcessTaskEnqueueStatements fans out one TaskSendStatement per account. Each task gets an ID derived from
+// the account and the month and is retained after completion, so running the fan-out again within
+// statementTaskRetention does not send a statement twice.
+func (processor *RedisTaskProcessor) ProcessTaskEnqueueStatements(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadEnqueueStatements
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	period := statement.PreviousMonth(time.Now())
+	if !payload.PeriodStart.IsZero() {
+		period = statement.MonthOf(payload.PeriodStart)
+	}
+
+	accountIDs, err := processor.store.ListStatementAccountIDs(ctx, db.ListStatementAccountIDsParams{
+		PeriodEnd:   period.End,
+		PeriodStart: period.Start,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	for _, accountID := range accountIDs {
+		err := processor.distributor.DistributeTaskSendStatement(ctx, &PayloadSendStatement{
+			AccountID:   accountID,
+			PeriodStart: period.Start,
+		},
+			asynq.TaskID(fmt.Sprintf("statement:%d:%s", accountID, period)),
+			asynq.MaxRetry(5),
+			asynq.Queue(QueueDefault),
+			asynq.Retention(statementTaskRetention),
+		)
+		if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+			return fmt.Errorf("failed to distribute statement of account %d: %w", accountID, err)
+		}
+	}
+
+	log.Info().Str("type", task.Type()).Str("period", period.String()).Int("accounts", len(accountIDs)).Msg("processed task")
+	return nil
+}
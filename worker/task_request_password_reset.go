@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskRequestPasswordReset = "task:request_password_reset"
+
+// passwordResetRequestInterval is the minimum time between two password reset emails of a user
+const passwordResetRequestInterval = 5 * time.Minute
+
+// PayloadRequestPasswordReset carries the email as entered, the RPC does not look it up so that
+// known and unknown emails take the same time to answer
+type PayloadRequestPasswordReset struct {
+	Email string `json:"email"`
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskRequestPasswordReset(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadRequestPasswordReset
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	user, err := processor.store.GetUserByEmail(ctx, payload.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Info().Str("type", task.Type()).Msg("skipped password reset of unknown email")
+			return nil
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	result, err := processor.store.RequestPasswordResetTx(ctx, db.RequestPasswordResetTxParams{
+		Username:   user.Username,
+		SecretCode: util.RandomString(32),
+		Cooldown:   passwordResetRequestInterval,
+		AfterCreate: func(passwordReset db.PasswordResets) (db.CreateOutboxMessageParams, error) {
+			taskPayload := &PayloadSendPasswordReset{
+				Username: passwordReset.Username,
+				ResetID:  passwordReset.ID,
+			}
+			return NewOutboxMessage(TaskSendPasswordReset, taskPayload, QueueCritical, 10)
+		},
+	})
+	if err != nil {
+		// the code sent moments ago is still valid
+		if errors.Is(err, db.ErrPasswordResetTooSoon) {
+			log.Info().Str("type", task.Type()).Str("username", user.Username).Msg("skipped password reset requested too soon")
+			return nil
+		}
+		return fmt.Errorf("failed to request password reset: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Int64("reset_id", result.PasswordReset.ID).Msg("processed task")
+	return nil
+}
@@ -0,0 +1,110 @@
+package worker_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/guncv/Simple-Bank/db/mock"
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/util"
+	"github.com/guncv/Simple-Bank/worker"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTaskRequestPasswordReset(t *testing.T) {
+	user := db.Users{
+		Username: util.RandomOwner(),
+		Email:    util.RandomEmail(),
+		Role:     string(util.DepositorRole),
+	}
+	passwordReset := db.PasswordResets{ID: 1, Username: user.Username, Email: user.Email}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().RequestPasswordResetTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.RequestPasswordResetTxParams) (db.RequestPasswordResetTxResult, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.NotEmpty(t, arg.SecretCode)
+						require.Positive(t, arg.Cooldown)
+
+						// the email is queued through the outbox in the same transaction
+						message, err := arg.AfterCreate(passwordReset)
+						require.NoError(t, err)
+						require.Equal(t, worker.TaskSendPasswordReset, message.TaskType)
+						require.Equal(t, worker.QueueCritical, message.Queue)
+
+						var payload worker.PayloadSendPasswordReset
+						require.NoError(t, json.Unmarshal(message.Payload, &payload))
+						require.Equal(t, worker.PayloadSendPasswordReset{Username: user.Username, ResetID: passwordReset.ID}, payload)
+						return db.RequestPasswordResetTxResult{PasswordReset: passwordReset}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "RequestedRecently",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().RequestPasswordResetTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.RequestPasswordResetTxResult{}, db.ErrPasswordResetTooSoon)
+			},
+			checkResponse: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "UnknownEmail",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(db.Users{}, sql.ErrNoRows)
+				store.EXPECT().RequestPasswordResetTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(db.Users{}, sql.ErrConnDone)
+				store.EXPECT().RequestPasswordResetTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, err error) {
+				// the task is retried
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			processor := worker.NewRedisTaskProcessor(asynq.RedisClientOpt{}, store, nil, nil, "", nil)
+
+			payload, err := json.Marshal(worker.PayloadRequestPasswordReset{Email: user.Email})
+			require.NoError(t, err)
+
+			err = processor.ProcessTaskRequestPasswordReset(context.Background(), asynq.NewTask(worker.TaskRequestPasswordReset, payload))
+			tc.checkResponse(t, err)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSendPasswordReset = "task:send_password_reset"
+
+type PayloadSendPasswordReset struct {
+	Username string `json:"username"`
+	ResetID  int64  `json:"reset_id"`
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskSendPasswordReset(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendPasswordReset
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	passwordReset, err := processor.store.GetPasswordReset(ctx, payload.ResetID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("password reset not found")
+		}
+		return fmt.Errorf("failed to get password reset: %w", err)
+	}
+
+	user, err := processor.store.GetUser(ctx, passwordReset.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("user not found")
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	// a newer code replaced this one or the email changed since the request
+	if passwordReset.IsUsed || passwordReset.Email != user.Email || time.Now().After(passwordReset.ExpiredAt) {
+		log.Info().Str("type", task.Type()).Int64("reset_id", passwordReset.ID).Msg("skipped stale password reset")
+		return nil
+	}
+
+	subject := "Reset your Simple Bank password"
+	resetUrl := fmt.Sprintf("http://localhost:8080/reset_password?reset_id=%d&secret_code=%s", passwordReset.ID, passwordReset.SecretCode)
+	content := fmt.Sprintf(`Hello %s,<br>
+		We received a request to reset your password.<br>
+		The link below expires in 15 minutes:<br>
+		<a href="%s">click here</a><br>
+		If you did not request this, you can safely ignore this email.
+		`, user.Username, resetUrl)
+	to := []string{user.Email}
+
+	if err = processor.mailer.SendEmail(subject, content, to, nil, nil, nil); err != nil {
+		return fmt.Errorf("failed to send password reset email: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Str("payload", string(task.Payload())).Str("email", user.Email).Msg("processed task")
+	return nil
+}
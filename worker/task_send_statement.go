@@ -0,0 +1,109 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	db "github.com/guncv/Simple-Bank/db/sqlc"
+	"github.com/guncv/Simple-Bank/statement"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSendStatement = "task:send_statement"
+
+type PayloadSendStatement struct {
+	AccountID int64 `json:"account_id"`
+	// PeriodStart is any time within the month of the statement
+	PeriodStart time.Time `json:"period_start"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskSendStatement(
+	ctx context.Context,
+	payload *PayloadSendStatement,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskSendStatement, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", info.Type).
+		Str("queue", info.Queue).
+		Int("max_retry", info.MaxRetry).
+		Str("payload", string(jsonPayload)).
+		Msg("enqueued task")
+	return nil
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskSendStatement(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendStatement
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	period := statement.MonthOf(payload.PeriodStart)
+
+	account, err := processor.store.GetAccount(ctx, payload.AccountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("account not found: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+
+	user, err := processor.store.GetUser(ctx, account.Owner)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	openingBalance, err := processor.store.GetAccountBalanceAt(ctx, db.GetAccountBalanceAtParams{
+		At:        period.Start,
+		AccountID: account.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get opening balance: %w", err)
+	}
+
+	entries, err := processor.store.ListStatementEntries(ctx, db.ListStatementEntriesParams{
+		AccountID:   account.ID,
+		PeriodStart: period.Start,
+		PeriodEnd:   period.End,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list entries: %w", err)
+	}
+
+	accountStatement := statement.New(account, user, period, openingBalance, entries, processor.currencies.MinorUnit(account.Currency))
+	files, err := statement.Save(processor.statementDir, accountStatement)
+	if err != nil {
+		return fmt.Errorf("failed to save statement: %w", err)
+	}
+
+	subject := fmt.Sprintf("Your Simple Bank statement for %s", period)
+	content := fmt.Sprintf(`Hello %s,<br>
+		Please find attached the statement of account %d for %s in PDF and CSV format.<br>
+		Opening balance: %s<br>
+		Closing balance: %s
+		`, user.FullName, account.ID, period,
+		processor.currencies.FormatAmount(accountStatement.OpeningBalance, account.Currency),
+		processor.currencies.FormatAmount(accountStatement.ClosingBalance, account.Currency),
+	)
+	to := []string{user.Email}
+
+	if err = processor.mailer.SendEmail(subject, content, to, nil, nil, files); err != nil {
+		return fmt.Errorf("failed to send statement email: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Str("payload", string(task.Payload())).Str("email", user.Email).Msg("processed task")
+	return nil
+}
@@ -18,30 +18,6 @@ type PayloadSendVerifyEmail struct {
 	Username string `json:"username"`
 }
 
-func (distributor *RedisTaskDistributor) DistributeTaskSendVerifyEmail(
-	ctx context.Context,
-	payload *PayloadSendVerifyEmail,
-	opts ...asynq.Option,
-) error {
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal task payload: %w", err)
-	}
-
-	task := asynq.NewTask(TaskSendVerifyEmail, jsonPayload, opts...)
-	info, err := distributor.client.EnqueueContext(ctx, task)
-	if err != nil {
-		return fmt.Errorf("failed to enqueue task: %w", err)
-	}
-
-	log.Info().Str("type", info.Type).
-		Str("queue", info.Queue).
-		Int("max_retry", info.MaxRetry).
-		Str("payload", string(jsonPayload)).
-		Msg("enqueued task")
-	return nil
-}
-
 func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Context, task *asynq.Task) error {
 	var payload PayloadSendVerifyEmail
 	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
@@ -56,6 +32,11 @@ func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Cont
 		return fmt.Errorf("failed to get user: %w", err)
 	}
 
+	// only the most recently sent code can be used
+	if err = processor.store.InvalidateVerifyEmails(ctx, user.Username); err != nil {
+		return fmt.Errorf("failed to invalidate verify emails: %w", err)
+	}
+
 	verifyEmail, err := processor.store.CreateVerifyEmail(ctx, db.CreateVerifyEmailParams{
 		Username:   user.Username,
 		Email:      user.Email,